@@ -0,0 +1,17 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// Hub marks SopsSecret as the conversion hub. All other versions convert
+// from and to v1alpha1, which remains the storage version.
+func (*SopsSecret) Hub() {}
+
+// Hub marks GlobalSopsSecret as the conversion hub.
+func (*GlobalSopsSecret) Hub() {}
+
+// Hub marks SopsProvider as the conversion hub.
+func (*SopsProvider) Hub() {}
+
+// Hub marks NamespacedSopsProvider as the conversion hub.
+func (*NamespacedSopsProvider) Hub() {}
@@ -18,6 +18,14 @@ type GlobalSopsSecretSpec struct {
 	// Define additional Metadata for the generated secrets
 	// +optional
 	Metadata SecretMetadata `json:"metadata,omitzero"`
+
+	// Define how drift on the generated secrets is handled:
+	// - Repair: Overwrite drifted secrets with the desired state
+	// - Report: Only report drift, without modifying the secrets
+	// - AllowExtraKeys: Repair managed keys, but keep keys added by others
+	// +kubebuilder:default=Repair
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
 // GlobalSopsSecretItem defines the desired state of GlobalSopsSecret.
@@ -34,6 +42,7 @@ func (s *GlobalSopsSecret) GetSopsMetadata() *api.Metadata {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:printcolumn:name="Secrets",type="integer",JSONPath=".status.size",description="The amount of secrets being managed"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Reconcile Status"
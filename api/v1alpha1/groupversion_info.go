@@ -27,6 +27,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(GroupVersion,
 		&GlobalSopsSecret{},
 		&GlobalSopsSecretList{},
+		&NamespacedSopsProvider{},
+		&NamespacedSopsProviderList{},
 		&SopsProvider{},
 		&SopsProviderList{},
 		&SopsSecret{},
@@ -0,0 +1,114 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ProviderName identifies the provider in the status of SopsSecrets and the
+// metrics. It is qualified by the namespace, to be distinct from SopsProviders.
+func (s *NamespacedSopsProvider) ProviderName() string {
+	return s.Namespace + "/" + s.Name
+}
+
+// SelectsSecret returns true if any secret selector of the provider matches
+// the given SopsSecret. SopsSecrets in other namespaces are only selected,
+// when tenant namespaces are enabled and both namespaces belong to the same
+// Capsule tenant. Selectors which can not be evaluated are skipped.
+func (s *NamespacedSopsProvider) SelectsSecret(ctx context.Context, c client.Client, secret metav1.Object) bool {
+	if secret.GetNamespace() == "" {
+		return false
+	}
+
+	if secret.GetNamespace() != s.Namespace {
+		if !s.Spec.TenantNamespaces {
+			return false
+		}
+
+		same, err := sameTenant(ctx, c, s.Namespace, secret.GetNamespace())
+		if err != nil || !same {
+			return false
+		}
+	}
+
+	for _, selector := range s.Spec.SecretSelectors {
+		sel, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			continue
+		}
+
+		if sel.Matches(labels.Set(secret.GetLabels())) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SelectKeySecrets returns the key Secrets selected by the provider from the
+// candidates, by UID. Only Secrets in the namespace of the provider are
+// considered and Secrets being deleted are skipped. Selectors which can not
+// be evaluated are reported as error, the Secrets selected by the other
+// selectors are still returned.
+func (s *NamespacedSopsProvider) SelectKeySecrets(candidates []*corev1.Secret) (selected map[string]*corev1.Secret, err error) {
+	selected = make(map[string]*corev1.Secret)
+
+	for _, selector := range s.Spec.KeySelectors {
+		sel, serr := metav1.LabelSelectorAsSelector(selector)
+		if serr != nil {
+			err = errors.Join(err, fmt.Errorf("invalid key selector: %w", serr))
+
+			continue
+		}
+
+		for _, secret := range candidates {
+			if secret.Namespace != s.Namespace || !secret.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			if sel.Matches(labels.Set(secret.Labels)) {
+				selected[string(secret.UID)] = secret
+			}
+		}
+	}
+
+	return selected, err
+}
+
+// Both namespaces are labeled with the same Capsule tenant.
+func sameTenant(ctx context.Context, c client.Client, a, b string) (bool, error) {
+	tenantA, err := namespaceTenant(ctx, c, a)
+	if err != nil || tenantA == "" {
+		return false, err
+	}
+
+	tenantB, err := namespaceTenant(ctx, c, b)
+	if err != nil {
+		return false, err
+	}
+
+	return tenantA == tenantB, nil
+}
+
+func namespaceTenant(ctx context.Context, c client.Client, name string) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		return "", err
+	}
+
+	if tenant := ns.Labels[capmeta.NewTenantLabel]; tenant != "" {
+		return tenant, nil
+	}
+
+	return ns.Labels[capmeta.TenantLabel], nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespacedSopsProviderSpec defines the desired state of NamespacedSopsProvider.
+type NamespacedSopsProviderSpec struct {
+	// Select the SopsSecrets in the namespace of the provider, which may be
+	// decrypted with the keys of this provider
+	SecretSelectors []*metav1.LabelSelector `json:"secretSelectors"`
+	// Select the Secrets in the namespace of the provider, the private keys
+	// of this provider are sourced from
+	KeySelectors []*metav1.LabelSelector `json:"keySelectors"`
+	// Also select SopsSecrets in the other namespaces of the Capsule tenant,
+	// the namespace of the provider belongs to
+	// +optional
+	TenantNamespaces bool `json:"tenantNamespaces,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Keys",type="integer",JSONPath=".status.size",description="The amount of loaded key secrets"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Reconcile Status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description="Reconcile Message"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
+
+// NamespacedSopsProvider is the Schema for the namespacedsopsproviders API.
+// Its keys and the SopsSecrets it serves are confined to its namespace.
+type NamespacedSopsProvider struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	Spec NamespacedSopsProviderSpec `json:"spec"`
+	// +optional
+	Status SopsProviderStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NamespacedSopsProviderList contains a list of NamespacedSopsProvider.
+type NamespacedSopsProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitzero"`
+
+	Items []NamespacedSopsProvider `json:"items"`
+}
@@ -0,0 +1,26 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// DriftPolicy defines how drift on generated secrets is handled.
+// +kubebuilder:validation:Enum=Repair;Report;AllowExtraKeys
+type DriftPolicy string
+
+const (
+	// DriftPolicyRepair overwrites drifted secrets with the desired state.
+	DriftPolicyRepair DriftPolicy = "Repair"
+	// DriftPolicyReport reports drift without modifying the secrets.
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyAllowExtraKeys repairs managed keys but keeps keys added by others.
+	DriftPolicyAllowExtraKeys DriftPolicy = "AllowExtraKeys"
+)
+
+// OrDefault returns the policy or the default policy when unset.
+func (p DriftPolicy) OrDefault() DriftPolicy {
+	if p == "" {
+		return DriftPolicyRepair
+	}
+
+	return p
+}
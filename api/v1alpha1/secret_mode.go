@@ -0,0 +1,24 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// SecretMode defines how decrypted data is written to a secret.
+// +kubebuilder:validation:Enum=Create;Merge
+type SecretMode string
+
+const (
+	// SecretModeCreate creates and owns the secret.
+	SecretModeCreate SecretMode = "Create"
+	// SecretModeMerge merges the decrypted keys into an existing secret.
+	SecretModeMerge SecretMode = "Merge"
+)
+
+// OrDefault returns the mode or the default mode when unset.
+func (m SecretMode) OrDefault() SecretMode {
+	if m == "" {
+		return SecretModeCreate
+	}
+
+	return m
+}
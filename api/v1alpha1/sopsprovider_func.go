@@ -5,8 +5,10 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/peak-scale/sops-operator/internal/api"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,6 +49,55 @@ func (s *SopsProvider) GatherProviderSecrets(ctx context.Context, client client.
 	return finalSecrets, nil
 }
 
+// SelectsSecret returns true if any SOPS selector of the provider matches the
+// given SopsSecret or GlobalSopsSecret. Selectors which can not be evaluated
+// are skipped.
+func (s *SopsProvider) SelectsSecret(ctx context.Context, client client.Client, secret metav1.Object) bool {
+	for _, selector := range s.Spec.SOPSSelectors {
+		match, err := selector.SingleMatch(ctx, client, secret)
+		if err != nil {
+			continue
+		}
+
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SelectKeySecrets returns the key Secrets selected by the provider from the
+// candidates, by UID. Secrets being deleted are skipped. Selectors which can
+// not be evaluated are reported as error, the Secrets selected by the other
+// selectors are still returned.
+func (s *SopsProvider) SelectKeySecrets(
+	ctx context.Context,
+	c client.Client,
+	candidates []*corev1.Secret,
+) (selected map[string]*corev1.Secret, err error) {
+	selected = make(map[string]*corev1.Secret)
+
+	for _, selector := range s.Spec.ProviderSecrets {
+		matchingSecrets, merr := api.MatchTypedObjects(ctx, c, selector, candidates)
+		if merr != nil {
+			err = errors.Join(err, merr)
+
+			continue
+		}
+
+		for _, secret := range matchingSecrets {
+			if !secret.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			selected[string(secret.UID)] = secret
+		}
+	}
+
+	return selected, err
+}
+
 // Helper function to convert []corev1.Secret to []metav1.Object.
 func toObjectList(secrets []corev1.Secret) []metav1.Object {
 	objectList := make([]metav1.Object, len(secrets))
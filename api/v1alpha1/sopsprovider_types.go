@@ -15,11 +15,18 @@ type SopsProviderSpec struct {
 	SOPSSelectors []*api.NamespacedSelector `json:"sops"`
 	// Select namespaces or secrets where decryption information for this
 	// provider can be sourced from
-	ProviderSecrets []*api.NamespacedSelector `json:"keys"`
+	// +optional
+	ProviderSecrets []*api.NamespacedSelector `json:"keys,omitempty"`
+	// Secrets where decryption information for this provider is sourced
+	// from, referenced explicitly. They are read directly from the API server
+	// and don't require the key Secret label, so access can be limited to them
+	// +optional
+	KeySecretRefs []*api.KeySecretReference `json:"keySecretRefs,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Reconcile Status"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description="Reconcile Message"
@@ -153,4 +153,10 @@ type SopsSecretItemStatus struct {
 	Name      string           `json:"name"`
 	Namespace string           `json:"namespace"`
 	UID       k8stypes.UID     `json:"uid,omitempty"`
+	// Mode the secret was written with
+	// +optional
+	Mode SecretMode `json:"mode,omitempty"`
+	// Fields managed by other field managers which conflicted when merging
+	// +optional
+	Conflicts []string `json:"conflicts,omitempty"`
 }
@@ -17,6 +17,14 @@ type SopsSecretSpec struct {
 	// Define additional Metadata for the generated secrets
 	// +optional
 	Metadata SecretMetadata `json:"metadata,omitzero"`
+
+	// Define how drift on the generated secrets is handled:
+	// - Repair: Overwrite drifted secrets with the desired state
+	// - Report: Only report drift, without modifying the secrets
+	// - AllowExtraKeys: Repair managed keys, but keep keys added by others
+	// +kubebuilder:default=Repair
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
 // SopsSecretTemplate defines the map of secrets to create
@@ -62,12 +70,29 @@ type SopsSecretItem struct {
 	// information: https://kubernetes.io/docs/concepts/configuration/secret/#overview-of-secrets)
 	//+optional
 	StringData map[string]string `json:"stringData,omitempty"`
+	// Reference SOPS encrypted documents stored in ConfigMaps or Secrets. The
+	// documents are decrypted and their top-level entries become keys of the
+	// secret. Binary documents are stored under the referenced key.
+	// Keys defined in data or stringData take precedence.
+	// +optional
+	DataFrom []SopsDataSource `json:"dataFrom,omitempty"`
+	// SOPS encrypted dotenv or INI document. Each variable (section.key for INI)
+	// becomes a key of the secret. Takes precedence over dataFrom, keys defined
+	// in data or stringData take precedence over the document.
+	// +optional
+	EnvData *SopsEnvData `json:"envData,omitempty"`
 	// Immutable, if set to true, ensures that data stored in the Secret cannot
 	// be updated (only object metadata can be modified).
 	// If not set to true, the field can be modified at any time.
 	// Defaulted to nil.
 	// +optional
 	Immutable *bool `json:"immutable,omitempty" protobuf:"varint,5,opt,name=immutable"`
+	// Mode defines how the decrypted data is written:
+	// - Create: Create and own the secret
+	// - Merge: Merge the decrypted keys into an existing secret, which is not owned by the operator
+	// +kubebuilder:default=Create
+	// +optional
+	Mode SecretMode `json:"mode,omitempty"`
 }
 
 func (s *SopsSecret) GetSopsMetadata() *api.Metadata {
@@ -76,6 +101,7 @@ func (s *SopsSecret) GetSopsMetadata() *api.Metadata {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Secrets",type="integer",JSONPath=".status.size",description="The amount of secrets being managed"
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Reconcile Status"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description="Reconcile Message"
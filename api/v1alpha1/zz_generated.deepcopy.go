@@ -23,6 +23,8 @@ package v1alpha1
 import (
 	"github.com/peak-scale/sops-operator/internal/api"
 	"github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -133,6 +135,102 @@ func (in *GlobalSopsSecretSpec) DeepCopy() *GlobalSopsSecretSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedSopsProvider) DeepCopyInto(out *NamespacedSopsProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedSopsProvider.
+func (in *NamespacedSopsProvider) DeepCopy() *NamespacedSopsProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedSopsProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedSopsProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedSopsProviderList) DeepCopyInto(out *NamespacedSopsProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NamespacedSopsProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedSopsProviderList.
+func (in *NamespacedSopsProviderList) DeepCopy() *NamespacedSopsProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedSopsProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NamespacedSopsProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedSopsProviderSpec) DeepCopyInto(out *NamespacedSopsProviderSpec) {
+	*out = *in
+	if in.SecretSelectors != nil {
+		in, out := &in.SecretSelectors, &out.SecretSelectors
+		*out = make([]*v1.LabelSelector, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(v1.LabelSelector)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.KeySelectors != nil {
+		in, out := &in.KeySelectors, &out.KeySelectors
+		*out = make([]*v1.LabelSelector, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(v1.LabelSelector)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedSopsProviderSpec.
+func (in *NamespacedSopsProviderSpec) DeepCopy() *NamespacedSopsProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedSopsProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretMetadata) DeepCopyInto(out *SecretMetadata) {
 	*out = *in
@@ -162,6 +260,46 @@ func (in *SecretMetadata) DeepCopy() *SecretMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsDataSource) DeepCopyInto(out *SopsDataSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsDataSource.
+func (in *SopsDataSource) DeepCopy() *SopsDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SopsEnvData) DeepCopyInto(out *SopsEnvData) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsEnvData.
+func (in *SopsEnvData) DeepCopy() *SopsEnvData {
+	if in == nil {
+		return nil
+	}
+	out := new(SopsEnvData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SopsProvider) DeepCopyInto(out *SopsProvider) {
 	*out = *in
@@ -263,6 +401,17 @@ func (in *SopsProviderSpec) DeepCopyInto(out *SopsProviderSpec) {
 			}
 		}
 	}
+	if in.KeySecretRefs != nil {
+		in, out := &in.KeySecretRefs, &out.KeySecretRefs
+		*out = make([]*api.KeySecretReference, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(api.KeySecretReference)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsProviderSpec.
@@ -372,6 +521,18 @@ func (in *SopsSecretItem) DeepCopyInto(out *SopsSecretItem) {
 			(*out)[key] = val
 		}
 	}
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = make([]SopsDataSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvData != nil {
+		in, out := &in.EnvData, &out.EnvData
+		*out = new(SopsEnvData)
+		**out = **in
+	}
 	if in.Immutable != nil {
 		in, out := &in.Immutable, &out.Immutable
 		*out = new(bool)
@@ -393,6 +554,11 @@ func (in *SopsSecretItem) DeepCopy() *SopsSecretItem {
 func (in *SopsSecretItemStatus) DeepCopyInto(out *SopsSecretItemStatus) {
 	*out = *in
 	in.Condition.DeepCopyInto(&out.Condition)
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SopsSecretItemStatus.
@@ -0,0 +1,359 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"github.com/peak-scale/sops-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this SopsSecret to the hub version (v1alpha1).
+func (s *SopsSecret) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.SopsSecret)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Sops = s.Sops
+	dst.Spec = v1alpha1.SopsSecretSpec{
+		Metadata:    v1alpha1.SecretMetadata(s.Spec.Metadata),
+		DriftPolicy: v1alpha1.DriftPolicy(s.Spec.DriftPolicy),
+	}
+
+	for _, item := range s.Spec.Secrets {
+		dst.Spec.Secrets = append(dst.Spec.Secrets, itemToHub(item))
+	}
+
+	dst.Status = statusToHub(s.Status)
+
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1alpha1) to this SopsSecret.
+func (s *SopsSecret) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.SopsSecret)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	s.ObjectMeta = src.ObjectMeta
+	s.Sops = src.Sops
+	s.Spec = SopsSecretSpec{
+		Metadata:    SecretMetadata(src.Spec.Metadata),
+		DriftPolicy: DriftPolicy(src.Spec.DriftPolicy),
+	}
+
+	for _, item := range src.Spec.Secrets {
+		s.Spec.Secrets = append(s.Spec.Secrets, itemFromHub(item))
+	}
+
+	s.Status = statusFromHub(src.Status)
+
+	return nil
+}
+
+// ConvertTo converts this GlobalSopsSecret to the hub version (v1alpha1).
+func (s *GlobalSopsSecret) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.GlobalSopsSecret)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = s.ObjectMeta
+	dst.Sops = s.Sops
+	dst.Spec = v1alpha1.GlobalSopsSecretSpec{
+		Metadata:    v1alpha1.SecretMetadata(s.Spec.Metadata),
+		DriftPolicy: v1alpha1.DriftPolicy(s.Spec.DriftPolicy),
+	}
+
+	for _, item := range s.Spec.Secrets {
+		if item == nil {
+			dst.Spec.Secrets = append(dst.Spec.Secrets, nil)
+
+			continue
+		}
+
+		dst.Spec.Secrets = append(dst.Spec.Secrets, &v1alpha1.GlobalSopsSecretItem{
+			SopsSecretItem: *itemToHub(&item.SopsSecretItem),
+			Namespace:      item.Namespace,
+		})
+	}
+
+	dst.Status = statusToHub(s.Status)
+
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1alpha1) to this GlobalSopsSecret.
+func (s *GlobalSopsSecret) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.GlobalSopsSecret)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	s.ObjectMeta = src.ObjectMeta
+	s.Sops = src.Sops
+	s.Spec = GlobalSopsSecretSpec{
+		Metadata:    SecretMetadata(src.Spec.Metadata),
+		DriftPolicy: DriftPolicy(src.Spec.DriftPolicy),
+	}
+
+	for _, item := range src.Spec.Secrets {
+		if item == nil {
+			s.Spec.Secrets = append(s.Spec.Secrets, nil)
+
+			continue
+		}
+
+		s.Spec.Secrets = append(s.Spec.Secrets, &GlobalSopsSecretItem{
+			SopsSecretItem: *itemFromHub(&item.SopsSecretItem),
+			Namespace:      item.Namespace,
+		})
+	}
+
+	s.Status = statusFromHub(src.Status)
+
+	return nil
+}
+
+// ConvertTo converts this SopsProvider to the hub version (v1alpha1).
+func (p *SopsProvider) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.SopsProvider)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = p.ObjectMeta
+	dst.Spec = v1alpha1.SopsProviderSpec{
+		SOPSSelectors:   p.Spec.SecretSelectors,
+		ProviderSecrets: p.Spec.KeySelectors,
+		KeySecretRefs:   p.Spec.KeySecretRefs,
+	}
+	dst.Status = providerStatusToHub(p.Status)
+
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1alpha1) to this SopsProvider.
+func (p *SopsProvider) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.SopsProvider)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	p.ObjectMeta = src.ObjectMeta
+	p.Spec = SopsProviderSpec{
+		SecretSelectors: src.Spec.SOPSSelectors,
+		KeySelectors:    src.Spec.ProviderSecrets,
+		KeySecretRefs:   src.Spec.KeySecretRefs,
+	}
+	p.Status = providerStatusFromHub(src.Status)
+
+	return nil
+}
+
+// ConvertTo converts this NamespacedSopsProvider to the hub version (v1alpha1).
+func (p *NamespacedSopsProvider) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.NamespacedSopsProvider)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	dst.ObjectMeta = p.ObjectMeta
+	dst.Spec = v1alpha1.NamespacedSopsProviderSpec(p.Spec)
+	dst.Status = providerStatusToHub(p.Status)
+
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1alpha1) to this NamespacedSopsProvider.
+func (p *NamespacedSopsProvider) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.NamespacedSopsProvider)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", hub)
+	}
+
+	p.ObjectMeta = src.ObjectMeta
+	p.Spec = NamespacedSopsProviderSpec(src.Spec)
+	p.Status = providerStatusFromHub(src.Status)
+
+	return nil
+}
+
+func itemToHub(item *SopsSecretItem) *v1alpha1.SopsSecretItem {
+	if item == nil {
+		return nil
+	}
+
+	dst := &v1alpha1.SopsSecretItem{
+		Name:        item.Name,
+		Labels:      item.Labels,
+		Annotations: item.Annotations,
+		Type:        item.Type,
+		Data:        item.Data,
+		StringData:  item.StringData,
+		Immutable:   item.Immutable,
+		Mode:        v1alpha1.SecretMode(item.Mode),
+	}
+
+	for _, source := range item.DataFrom {
+		dst.DataFrom = append(dst.DataFrom, v1alpha1.SopsDataSource{
+			ConfigMapKeyRef: source.ConfigMapKeyRef,
+			SecretKeyRef:    source.SecretKeyRef,
+			Format:          v1alpha1.DocumentFormat(source.Format),
+		})
+	}
+
+	if item.EnvData != nil {
+		dst.EnvData = &v1alpha1.SopsEnvData{
+			Format:  v1alpha1.DocumentFormat(item.EnvData.Format),
+			Content: item.EnvData.Content,
+		}
+	}
+
+	return dst
+}
+
+func itemFromHub(item *v1alpha1.SopsSecretItem) *SopsSecretItem {
+	if item == nil {
+		return nil
+	}
+
+	dst := &SopsSecretItem{
+		Name:        item.Name,
+		Labels:      item.Labels,
+		Annotations: item.Annotations,
+		Type:        item.Type,
+		Data:        item.Data,
+		StringData:  item.StringData,
+		Immutable:   item.Immutable,
+		Mode:        SecretMode(item.Mode),
+	}
+
+	for _, source := range item.DataFrom {
+		dst.DataFrom = append(dst.DataFrom, SopsDataSource{
+			ConfigMapKeyRef: source.ConfigMapKeyRef,
+			SecretKeyRef:    source.SecretKeyRef,
+			Format:          DocumentFormat(source.Format),
+		})
+	}
+
+	if item.EnvData != nil {
+		dst.EnvData = &SopsEnvData{
+			Format:  DocumentFormat(item.EnvData.Format),
+			Content: item.EnvData.Content,
+		}
+	}
+
+	return dst
+}
+
+func statusToHub(status SopsSecretStatus) v1alpha1.SopsSecretStatus {
+	dst := v1alpha1.SopsSecretStatus{
+		Size:               status.Size,
+		Providers:          status.Providers,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+
+	for _, item := range status.Secrets {
+		if item == nil {
+			dst.Secrets = append(dst.Secrets, nil)
+
+			continue
+		}
+
+		dst.Secrets = append(dst.Secrets, &v1alpha1.SopsSecretItemStatus{
+			Condition: item.Condition,
+			Name:      item.Name,
+			Namespace: item.Namespace,
+			UID:       item.UID,
+			Mode:      v1alpha1.SecretMode(item.Mode),
+			Conflicts: item.Conflicts,
+		})
+	}
+
+	return dst
+}
+
+// statusFromHub drops the deprecated condition field, which is superseded
+// by the list of conditions.
+func statusFromHub(status v1alpha1.SopsSecretStatus) SopsSecretStatus {
+	dst := SopsSecretStatus{
+		Size:               status.Size,
+		Providers:          status.Providers,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+
+	for _, item := range status.Secrets {
+		if item == nil {
+			dst.Secrets = append(dst.Secrets, nil)
+
+			continue
+		}
+
+		dst.Secrets = append(dst.Secrets, &SopsSecretItemStatus{
+			Condition: item.Condition,
+			Name:      item.Name,
+			Namespace: item.Namespace,
+			UID:       item.UID,
+			Mode:      SecretMode(item.Mode),
+			Conflicts: item.Conflicts,
+		})
+	}
+
+	return dst
+}
+
+func providerStatusToHub(status SopsProviderStatus) v1alpha1.SopsProviderStatus {
+	dst := v1alpha1.SopsProviderStatus{
+		ProvidersAmount:    status.Size,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+
+	for _, key := range status.KeySecrets {
+		if key == nil {
+			dst.Providers = append(dst.Providers, nil)
+
+			continue
+		}
+
+		dst.Providers = append(dst.Providers, &v1alpha1.SopsProviderItemStatus{
+			Condition: key.Condition,
+			Origin:    key.Origin,
+		})
+	}
+
+	return dst
+}
+
+// providerStatusFromHub drops the deprecated condition field, which is
+// superseded by the list of conditions.
+func providerStatusFromHub(status v1alpha1.SopsProviderStatus) SopsProviderStatus {
+	dst := SopsProviderStatus{
+		Size:               status.ProvidersAmount,
+		Conditions:         status.Conditions,
+		ObservedGeneration: status.ObservedGeneration,
+	}
+
+	for _, key := range status.Providers {
+		if key == nil {
+			dst.KeySecrets = append(dst.KeySecrets, nil)
+
+			continue
+		}
+
+		dst.KeySecrets = append(dst.KeySecrets, &SopsProviderKeySecretStatus{
+			Condition: key.Condition,
+			Origin:    key.Origin,
+		})
+	}
+
+	return dst
+}
@@ -0,0 +1,207 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSopsSecretConversion(t *testing.T) {
+	t.Parallel()
+
+	immutable := true
+
+	hub := &v1alpha1.SopsSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "default", Generation: 2},
+		Spec: v1alpha1.SopsSecretSpec{
+			Metadata:    v1alpha1.SecretMetadata{Prefix: "pre-", Labels: map[string]string{"app": "demo"}},
+			DriftPolicy: v1alpha1.DriftPolicyReport,
+			Secrets: []*v1alpha1.SopsSecretItem{
+				{
+					Name:       "credentials",
+					Type:       corev1.SecretTypeBasicAuth,
+					StringData: map[string]string{"username": "ENC[AES256_GCM,data:...]"},
+					Immutable:  &immutable,
+					Mode:       v1alpha1.SecretModeMerge,
+					DataFrom: []v1alpha1.SopsDataSource{{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "documents"},
+							Key:                  "app.yaml",
+						},
+						Format: v1alpha1.DocumentFormatYAML,
+					}},
+					EnvData: &v1alpha1.SopsEnvData{Format: v1alpha1.DocumentFormatDotenv, Content: "KEY=ENC[...]"},
+				},
+			},
+		},
+		Status: v1alpha1.SopsSecretStatus{
+			Size: 1,
+			Secrets: []*v1alpha1.SopsSecretItemStatus{{
+				Name:      "credentials",
+				Namespace: "default",
+				Mode:      v1alpha1.SecretModeMerge,
+				Conflicts: []string{"data.username"},
+			}},
+			Providers:          []*api.Origin{{Name: "provider"}},
+			Conditions:         capmeta.ConditionList{{Type: "Ready", Status: metav1.ConditionTrue}},
+			ObservedGeneration: 2,
+		},
+		Sops: &api.Metadata{Version: "3.9.0"},
+	}
+
+	spoke := &SopsSecret{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	require.Equal(t, "pre-", spoke.Spec.Metadata.Prefix)
+	require.Equal(t, DriftPolicyReport, spoke.Spec.DriftPolicy)
+	require.Equal(t, SecretModeMerge, spoke.Spec.Secrets[0].Mode)
+	require.Equal(t, "app.yaml", spoke.Spec.Secrets[0].DataFrom[0].ConfigMapKeyRef.Key)
+	require.Equal(t, DocumentFormatDotenv, spoke.Spec.Secrets[0].EnvData.Format)
+	require.Equal(t, "3.9.0", spoke.Sops.Version)
+	require.Equal(t, []string{"data.username"}, spoke.Status.Secrets[0].Conflicts)
+
+	converted := &v1alpha1.SopsSecret{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	require.Equal(t, hub, converted)
+}
+
+func TestSopsSecretConversionDropsDeprecatedCondition(t *testing.T) {
+	t.Parallel()
+
+	hub := &v1alpha1.SopsSecret{
+		Status: v1alpha1.SopsSecretStatus{
+			//nolint:staticcheck
+			Condition: metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue},
+		},
+	}
+
+	spoke := &SopsSecret{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	converted := &v1alpha1.SopsSecret{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	//nolint:staticcheck
+	require.Zero(t, converted.Status.Condition)
+}
+
+func TestGlobalSopsSecretConversion(t *testing.T) {
+	t.Parallel()
+
+	hub := &v1alpha1.GlobalSopsSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "global"},
+		Spec: v1alpha1.GlobalSopsSecretSpec{
+			Secrets: []*v1alpha1.GlobalSopsSecretItem{
+				{
+					SopsSecretItem: v1alpha1.SopsSecretItem{
+						Name: "credentials",
+						Data: map[string]string{"token": "ENC[AES256_GCM,data:...]"},
+					},
+					Namespace: "tenant",
+				},
+			},
+		},
+		Sops: &api.Metadata{Version: "3.9.0"},
+	}
+
+	spoke := &GlobalSopsSecret{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	require.Equal(t, "tenant", spoke.Spec.Secrets[0].Namespace)
+	require.Equal(t, "credentials", spoke.Spec.Secrets[0].Name)
+
+	converted := &v1alpha1.GlobalSopsSecret{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	require.Equal(t, hub, converted)
+}
+
+func TestSopsProviderConversion(t *testing.T) {
+	t.Parallel()
+
+	secrets := []*api.NamespacedSelector{{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	}}
+	keys := []*api.NamespacedSelector{{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"keys": "true"}},
+	}}
+
+	refs := []*api.KeySecretReference{{Namespace: "keys", Name: "age", Keys: []string{"key.agekey"}}}
+
+	hub := &v1alpha1.SopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "provider"},
+		Spec: v1alpha1.SopsProviderSpec{
+			SOPSSelectors:   secrets,
+			ProviderSecrets: keys,
+			KeySecretRefs:   refs,
+		},
+		Status: v1alpha1.SopsProviderStatus{
+			ProvidersAmount: 1,
+			Providers: []*v1alpha1.SopsProviderItemStatus{{
+				Condition: metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue},
+				Origin:    api.Origin{Name: "age", Namespace: "keys", UID: "uid"},
+			}},
+			Conditions: capmeta.ConditionList{{Type: "Ready", Status: metav1.ConditionTrue}},
+		},
+	}
+
+	spoke := &SopsProvider{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	require.Equal(t, secrets, spoke.Spec.SecretSelectors)
+	require.Equal(t, keys, spoke.Spec.KeySelectors)
+	require.Equal(t, refs, spoke.Spec.KeySecretRefs)
+	require.Equal(t, uint(1), spoke.Status.Size)
+	require.Equal(t, "age", spoke.Status.KeySecrets[0].Name)
+
+	converted := &v1alpha1.SopsProvider{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	require.Equal(t, hub, converted)
+}
+
+func TestNamespacedSopsProviderConversion(t *testing.T) {
+	t.Parallel()
+
+	hub := &v1alpha1.NamespacedSopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "provider", Namespace: "tenant-a"},
+		Spec: v1alpha1.NamespacedSopsProviderSpec{
+			SecretSelectors:  []*metav1.LabelSelector{{MatchLabels: map[string]string{"team": "a"}}},
+			KeySelectors:     []*metav1.LabelSelector{{MatchLabels: map[string]string{"keys": "true"}}},
+			TenantNamespaces: true,
+		},
+		Status: v1alpha1.SopsProviderStatus{
+			ProvidersAmount: 1,
+			Providers: []*v1alpha1.SopsProviderItemStatus{{
+				Condition: metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue},
+				Origin:    api.Origin{Name: "age", Namespace: "tenant-a", UID: "uid"},
+			}},
+		},
+	}
+
+	spoke := &NamespacedSopsProvider{}
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	require.True(t, spoke.Spec.TenantNamespaces)
+	require.Equal(t, uint(1), spoke.Status.Size)
+	require.Equal(t, "age", spoke.Status.KeySecrets[0].Name)
+
+	converted := &v1alpha1.NamespacedSopsProvider{}
+	require.NoError(t, spoke.ConvertTo(converted))
+	require.Equal(t, hub, converted)
+}
+
+func TestConversionRejectsOtherHub(t *testing.T) {
+	t.Parallel()
+
+	require.Error(t, (&SopsSecret{}).ConvertTo(&v1alpha1.SopsProvider{}))
+	require.Error(t, (&GlobalSopsSecret{}).ConvertFrom(&v1alpha1.SopsSecret{}))
+	require.Error(t, (&SopsProvider{}).ConvertTo(&v1alpha1.GlobalSopsSecret{}))
+	require.Error(t, (&NamespacedSopsProvider{}).ConvertFrom(&v1alpha1.SopsProvider{}))
+}
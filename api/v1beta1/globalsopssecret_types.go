@@ -0,0 +1,67 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"github.com/peak-scale/sops-operator/internal/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GlobalSopsSecretSpec defines the desired state of GlobalSopsSecret.
+type GlobalSopsSecretSpec struct {
+	// Secrets to generate, once the document is decrypted
+	Secrets []*GlobalSopsSecretItem `json:"secrets"`
+
+	// Additional metadata for the generated secrets
+	// +optional
+	Metadata SecretMetadata `json:"metadata,omitzero"`
+
+	// Define how drift on the generated secrets is handled:
+	// - Repair: Overwrite drifted secrets with the desired state
+	// - Report: Only report drift, without modifying the secrets
+	// - AllowExtraKeys: Repair managed keys, but keep keys added by others
+	// +kubebuilder:default=Repair
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// GlobalSopsSecretItem defines a secret generated in the given namespace.
+type GlobalSopsSecretItem struct {
+	SopsSecretItem `json:",inline"`
+
+	// Namespace of the generated secret
+	Namespace string `json:"namespace"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Secrets",type="integer",JSONPath=".status.size",description="The amount of secrets being managed"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Reconcile Status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description="Reconcile Message"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
+
+// GlobalSopsSecret is the Schema for the globalsopssecrets API.
+type GlobalSopsSecret struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	Spec GlobalSopsSecretSpec `json:"spec"`
+	// +optional
+	Status SopsSecretStatus `json:"status,omitzero"`
+	// SOPS metadata of the encrypted document, see SopsSecret
+	Sops *api.Metadata `json:"sops"`
+}
+
+// +kubebuilder:object:root=true
+
+// GlobalSopsSecretList contains a list of GlobalSopsSecret.
+type GlobalSopsSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitzero"`
+
+	Items []GlobalSopsSecret `json:"items"`
+}
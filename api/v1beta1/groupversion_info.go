@@ -0,0 +1,40 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+// Package v1beta1 contains API Schema definitions for the addons v1beta1 API group.
+// +kubebuilder:object:generate=true
+// +groupName=addons.projectcapsule.dev
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// GroupVersion is group version used to register these objects.
+	GroupVersion = schema.GroupVersion{Group: "addons.projectcapsule.dev", Version: "v1beta1"}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+
+	// AddToScheme adds the types in this group-version to the given scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(GroupVersion,
+		&GlobalSopsSecret{},
+		&GlobalSopsSecretList{},
+		&NamespacedSopsProvider{},
+		&NamespacedSopsProviderList{},
+		&SopsProvider{},
+		&SopsProviderList{},
+		&SopsSecret{},
+		&SopsSecretList{},
+	)
+	metav1.AddToGroupVersion(scheme, GroupVersion)
+
+	return nil
+}
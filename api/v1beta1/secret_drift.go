@@ -0,0 +1,17 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// DriftPolicy defines how drift on generated secrets is handled.
+// +kubebuilder:validation:Enum=Repair;Report;AllowExtraKeys
+type DriftPolicy string
+
+const (
+	// DriftPolicyRepair overwrites drifted secrets with the desired state.
+	DriftPolicyRepair DriftPolicy = "Repair"
+	// DriftPolicyReport reports drift without modifying the secrets.
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyAllowExtraKeys repairs managed keys but keeps keys added by others.
+	DriftPolicyAllowExtraKeys DriftPolicy = "AllowExtraKeys"
+)
@@ -0,0 +1,20 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+// SecretMetadata defines additional metadata for all generated secrets.
+type SecretMetadata struct {
+	// Prefix added to all generated Secrets names
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Suffix added to all generated Secrets names
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+	// Labels added to all generated Secrets
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to all generated Secrets
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
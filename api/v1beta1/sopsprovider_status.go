@@ -0,0 +1,35 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/projectcapsule/capsule/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SopsProviderStatus defines the observed state of SopsProvider.
+type SopsProviderStatus struct {
+	// Amount of selected key secrets
+	//+kubebuilder:default=0
+	Size uint `json:"size,omitempty"`
+	// Key secrets selected by this provider
+	// +optional
+	KeySecrets []*SopsProviderKeySecretStatus `json:"keySecrets,omitempty"`
+	// Conditions represent the latest available observations of the object
+	// +optional
+	Conditions meta.ConditionList `json:"conditions,omitempty"`
+	// ObservedGeneration is the most recent generation the controller has observed.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// SopsProviderKeySecretStatus defines the observed state of a key secret.
+type SopsProviderKeySecretStatus struct {
+	// Condition of the key secret
+	// +optional
+	Condition metav1.Condition `json:"condition,omitzero"`
+	// The secret the keys are loaded from
+	api.Origin `json:",inline"`
+}
@@ -0,0 +1,55 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"github.com/peak-scale/sops-operator/internal/api"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SopsProviderSpec defines the desired state of SopsProvider.
+type SopsProviderSpec struct {
+	// Select the SopsSecrets and GlobalSopsSecrets which may be decrypted
+	// with the keys of this provider
+	SecretSelectors []*api.NamespacedSelector `json:"secretSelectors"`
+	// Select the namespaces or secrets the private keys of this provider
+	// are sourced from
+	// +optional
+	KeySelectors []*api.NamespacedSelector `json:"keySelectors,omitempty"`
+	// Secrets the private keys of this provider are sourced from, referenced
+	// explicitly. They are read directly from the API server and don't
+	// require the key Secret label, so access can be limited to them
+	// +optional
+	KeySecretRefs []*api.KeySecretReference `json:"keySecretRefs,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Keys",type="integer",JSONPath=".status.size",description="The amount of loaded key secrets"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Reconcile Status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description="Reconcile Message"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
+
+// SopsProvider is the Schema for the sopsproviders API.
+type SopsProvider struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	Spec SopsProviderSpec `json:"spec"`
+	// +optional
+	Status SopsProviderStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// SopsProviderList contains a list of SopsProvider.
+type SopsProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitzero"`
+
+	Items []SopsProvider `json:"items"`
+}
@@ -0,0 +1,48 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DocumentFormat defines the format of a SOPS encrypted document.
+// +kubebuilder:validation:Enum=yaml;json;dotenv;ini;binary
+type DocumentFormat string
+
+const (
+	DocumentFormatYAML   DocumentFormat = "yaml"
+	DocumentFormatJSON   DocumentFormat = "json"
+	DocumentFormatDotenv DocumentFormat = "dotenv"
+	DocumentFormatINI    DocumentFormat = "ini"
+	DocumentFormatBinary DocumentFormat = "binary"
+)
+
+// SopsDataSource references a SOPS encrypted document, which is stored in a key
+// of a ConfigMap or Secret in the namespace of the generated secret.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapKeyRef) != has(self.secretKeyRef)",message="exactly one of configMapKeyRef or secretKeyRef must be set"
+type SopsDataSource struct {
+	// Selects a key of a ConfigMap containing the encrypted document
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Selects a key of a Secret containing the encrypted document
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Format of the encrypted document. Derived from the extension of the
+	// referenced key (.yaml, .yml, .json, .env, .ini) when not set, otherwise
+	// the document is treated as binary.
+	// +optional
+	Format DocumentFormat `json:"format,omitempty"`
+}
+
+// SopsEnvData is a SOPS encrypted dotenv or INI document.
+type SopsEnvData struct {
+	// Format of the encrypted document
+	// +kubebuilder:validation:Enum=dotenv;ini
+	// +kubebuilder:default=dotenv
+	// +optional
+	Format DocumentFormat `json:"format,omitempty"`
+	// SOPS encrypted document, as emitted by sops
+	Content string `json:"content"`
+}
@@ -0,0 +1,49 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/projectcapsule/capsule/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// SopsSecretStatus defines the observed state of SopsSecret and GlobalSopsSecret.
+type SopsSecretStatus struct {
+	// Amount of generated secrets
+	//+kubebuilder:default=0
+	Size uint `json:"size,omitempty"`
+	// Secrets generated by this object
+	// +optional
+	Secrets []*SopsSecretItemStatus `json:"secrets,omitempty"`
+	// Providers used to decrypt this object
+	// +optional
+	Providers []*api.Origin `json:"providers,omitempty"`
+	// Conditions represent the latest available observations of the object
+	// +optional
+	Conditions meta.ConditionList `json:"conditions,omitempty"`
+	// ObservedGeneration is the most recent generation the controller has observed.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// SopsSecretItemStatus defines the observed state of a generated secret.
+type SopsSecretItemStatus struct {
+	// Condition of the generated secret
+	Condition metav1.Condition `json:"condition"`
+	// Name of the generated secret
+	Name string `json:"name"`
+	// Namespace of the generated secret
+	Namespace string `json:"namespace"`
+	// UID of the generated secret
+	// +optional
+	UID k8stypes.UID `json:"uid,omitempty"`
+	// Mode the secret was written with
+	// +optional
+	Mode SecretMode `json:"mode,omitempty"`
+	// Fields managed by other field managers which conflicted when merging
+	// +optional
+	Conflicts []string `json:"conflicts,omitempty"`
+}
@@ -0,0 +1,106 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"github.com/peak-scale/sops-operator/internal/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SopsSecretSpec defines the desired state of SopsSecret.
+type SopsSecretSpec struct {
+	// Secrets to generate, once the document is decrypted
+	Secrets []*SopsSecretItem `json:"secrets"`
+
+	// Additional metadata for the generated secrets
+	// +optional
+	Metadata SecretMetadata `json:"metadata,omitzero"`
+
+	// Define how drift on the generated secrets is handled:
+	// - Repair: Overwrite drifted secrets with the desired state
+	// - Report: Only report drift, without modifying the secrets
+	// - AllowExtraKeys: Repair managed keys, but keep keys added by others
+	// +kubebuilder:default=Repair
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// SopsSecretItem defines a secret generated from the decrypted document.
+type SopsSecretItem struct {
+	// Name of the generated secret. Must be unique within a namespace.
+	Name string `json:"name"`
+	// Labels added to the generated secret
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations added to the generated secret
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Kubernetes secret type. Defaults to Opaque.
+	// +kubebuilder:validation:Enum=Opaque;kubernetes.io/service-account-token;kubernetes.io/dockercfg;kubernetes.io/dockerconfigjson;kubernetes.io/basic-auth;kubernetes.io/ssh-auth;kubernetes.io/tls;bootstrap.kubernetes.io/token
+	// +optional
+	Type corev1.SecretType `json:"type,omitempty"`
+	// Base64 encoded data of the secret, see
+	// https://kubernetes.io/docs/concepts/configuration/secret/#overview-of-secrets
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+	// Plain data of the secret, see
+	// https://kubernetes.io/docs/concepts/configuration/secret/#overview-of-secrets
+	// +optional
+	StringData map[string]string `json:"stringData,omitempty"`
+	// Reference SOPS encrypted documents stored in ConfigMaps or Secrets. The
+	// documents are decrypted and their top-level entries become keys of the
+	// secret. Binary documents are stored under the referenced key.
+	// Keys defined in data or stringData take precedence.
+	// +optional
+	DataFrom []SopsDataSource `json:"dataFrom,omitempty"`
+	// SOPS encrypted dotenv or INI document. Each variable (section.key for INI)
+	// becomes a key of the secret. Takes precedence over dataFrom, keys defined
+	// in data or stringData take precedence over the document.
+	// +optional
+	EnvData *SopsEnvData `json:"envData,omitempty"`
+	// Immutable, if set to true, ensures that data stored in the Secret cannot
+	// be updated (only object metadata can be modified).
+	// +optional
+	Immutable *bool `json:"immutable,omitempty"`
+	// Mode defines how the decrypted data is written:
+	// - Create: Create and own the secret
+	// - Merge: Merge the decrypted keys into an existing secret, which is not owned by the operator
+	// +kubebuilder:default=Create
+	// +optional
+	Mode SecretMode `json:"mode,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Secrets",type="integer",JSONPath=".status.size",description="The amount of secrets being managed"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Reconcile Status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message",description="Reconcile Message"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
+
+// SopsSecret is the Schema for the sopssecrets API.
+type SopsSecret struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	Spec SopsSecretSpec `json:"spec"`
+	// +optional
+	Status SopsSecretStatus `json:"status,omitzero"`
+	// SOPS metadata of the encrypted document. The sops CLI writes its metadata
+	// to the root of the document and authenticates the encrypted values by
+	// their path, therefore it can not be moved below spec.
+	Sops *api.Metadata `json:"sops"`
+}
+
+// +kubebuilder:object:root=true
+
+// SopsSecretList contains a list of SopsSecret.
+type SopsSecretList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitzero"`
+
+	Items []SopsSecret `json:"items"`
+}
@@ -104,3 +104,34 @@ Create the jobs fully-qualified Docker image to use
 {{- $joined := join "," $sizes -}}
 {{- sha256sum $joined -}}
 {{- end -}}
+
+{{/*
+Webhook Service Name
+*/}}
+{{- define "webhooks.serviceName" -}}
+{{- printf "%s-webhook" (include "helm.fullname" .) | trunc 63 | trimSuffix "-" -}}
+{{- end }}
+
+{{/*
+Webhook Certificate Secret Name
+*/}}
+{{- define "webhooks.secretName" -}}
+{{- default (printf "%s-webhook-tls" (include "helm.fullname" .)) .Values.webhooks.certificate.secretName -}}
+{{- end }}
+
+{{/*
+Verbs on Secrets. With the restricted secret cache, only the verbs used by the controller are granted
+*/}}
+{{- define "helm.secretVerbs" -}}
+{{- if .Values.args.restrictSecretCache -}}
+- get
+- list
+- watch
+- create
+- update
+- patch
+- delete
+{{- else -}}
+- "*"
+{{- end -}}
+{{- end }}
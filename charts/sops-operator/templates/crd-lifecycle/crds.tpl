@@ -32,6 +32,24 @@
         {{- $tmp := deepCopy $p | mergeOverwrite $patchContent -}}
         {{- $p = $tmp -}}
       {{- end -}}
+      {{/* Convert between the API versions, otherwise only serve the storage version */}}
+      {{- if $.Values.webhooks.enabled }}
+        {{- if $.Values.webhooks.certificate.certManager }}
+          {{- $_ := set $p.metadata.annotations "cert-manager.io/inject-ca-from" (printf "%s/%s" $.Release.Namespace (include "webhooks.serviceName" $)) -}}
+        {{- end }}
+        {{- $clientConfig := dict "service" (dict "name" (include "webhooks.serviceName" $) "namespace" $.Release.Namespace "path" "/convert" "port" 443) -}}
+        {{- if and (not $.Values.webhooks.certificate.certManager) $.Values.webhooks.certificate.caBundle }}
+          {{- $_ := set $clientConfig "caBundle" $.Values.webhooks.certificate.caBundle -}}
+        {{- end }}
+        {{- $_ := set $p.spec "conversion" (dict "strategy" "Webhook" "webhook" (dict "clientConfig" $clientConfig "conversionReviewVersions" (list "v1"))) -}}
+      {{- else }}
+        {{- range $version := $p.spec.versions }}
+          {{- if not $version.storage }}
+            {{- $_ := set $version "served" false -}}
+          {{- end }}
+        {{- end }}
+      {{- end }}
+
       {{- if $p }}
         {{- if $.Values.crds.inline }}
 {{- printf "---\n%s" (toYaml $p) | nindent 0 }}
@@ -8,44 +8,94 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/peak-scale/sops-operator/internal/sopschecker"
 )
 
 func main() {
 	var (
-		configPath string
-		requireAll bool
-		globs      stringListFlag
+		configPath       string
+		output           string
+		policyPath       string
+		gitDiff          string
+		requireAll       bool
+		manifests        bool
+		verifyRecipients bool
+		verifyDecrypt    bool
+		ignoreFile       string
+		maxKeyAge        string
+		rotationCommands bool
+		globs            stringListFlag
+		excludes         stringListFlag
+		keys             stringListFlag
 	)
 
 	flag.StringVar(&configPath, "config", "", "Path to a .sops.yaml file. If omitted, the checker discovers .sops.yaml from each file path.")
 	flag.Var(&globs, "glob", "File glob pattern to check. Can be provided multiple times. When set, positional files are ignored.")
+	flag.Var(&excludes, "exclude", "Pattern of files to exclude, using .gitignore syntax. Can be provided multiple times.")
+	flag.StringVar(&ignoreFile, "ignore-file", "", "Path to a file with .gitignore patterns of files to exclude. Defaults to .sopscheckerignore, when present.")
 	flag.BoolVar(&requireAll, "require-all", false, "Require every provided file to be SOPS encrypted, without checking .sops.yaml creation rules.")
+	flag.BoolVar(&manifests, "manifests", false, "Also check YAML files as Kubernetes manifests: flag plain Secrets with data and SopsSecrets which are not encrypted, per document. The .sops.yaml creation rules still apply.")
+	flag.BoolVar(&verifyRecipients, "verify-recipients", false, "Verify encrypted files are encrypted for exactly the keys of the matching .sops.yaml creation rule.")
+	flag.StringVar(&policyPath, "recipient-policy", "", "Path to a recipient policy file with allowed and required recipients. Implies --verify-recipients.")
+	flag.BoolVar(&verifyDecrypt, "verify-decrypt", false, "Decrypt every encrypted file with the keys provided with --keys, including the MAC verification.")
+	flag.Var(&keys, "keys", "Key file (*.agekey, *.asc, ...), directory of key files or Secret manifest used by --verify-decrypt. Can be provided multiple times.")
+	flag.StringVar(&maxKeyAge, "max-key-age", "", "Report encrypted files whose data key or master keys are older than the given age, such as 90d or 2160h.")
+	flag.BoolVar(&rotationCommands, "rotation-commands", false, "List the sops commands to rotate stale keys found with --max-key-age.")
+	flag.StringVar(&gitDiff, "git-diff", "", "Only check files added or modified compared to the given git revision. Use - to read the changed files from stdin, one per line.")
+	flag.StringVar(&output, "output", string(sopschecker.OutputText), "Output format: text, json, sarif or junit. Structured formats are written to stdout, text to stderr.")
 	flag.Parse()
 
-	failures, err := sopschecker.Check(sopschecker.Options{
-		ConfigPath: configPath,
-		Files:      flag.Args(),
-		Globs:      globs,
-		RequireAll: requireAll,
-	})
+	format, err := sopschecker.ParseOutputFormat(output)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "sops-checker: %v\n", err)
 		os.Exit(2)
 	}
 
-	if len(failures) == 0 {
-		return
+	var maxAge time.Duration
+	if maxKeyAge != "" {
+		if maxAge, err = sopschecker.ParseMaxAge(maxKeyAge); err != nil {
+			fmt.Fprintf(os.Stderr, "sops-checker: --max-key-age: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	result, err := sopschecker.Run(sopschecker.Options{
+		ConfigPath:       configPath,
+		Files:            flag.Args(),
+		Globs:            globs,
+		RequireAll:       requireAll,
+		Manifests:        manifests,
+		VerifyRecipients: verifyRecipients,
+		PolicyPath:       policyPath,
+		GitDiff:          gitDiff,
+		DiffInput:        os.Stdin,
+		IgnoreFile:       ignoreFile,
+		Excludes:         excludes,
+		VerifyDecrypt:    verifyDecrypt,
+		KeyPaths:         keys,
+		MaxKeyAge:        maxAge,
+		RotationCommands: rotationCommands,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sops-checker: %v\n", err)
+		os.Exit(2)
 	}
 
-	fmt.Fprintln(os.Stderr, "sops-checker: files requiring SOPS encryption are not encrypted:")
+	out := os.Stdout
+	if format == sopschecker.OutputText {
+		out = os.Stderr
+	}
 
-	for _, failure := range failures {
-		fmt.Fprintf(os.Stderr, "  - %s: %s\n", failure.Path, failure.Reason)
+	if err := result.Write(out, format); err != nil {
+		fmt.Fprintf(os.Stderr, "sops-checker: write output: %v\n", err)
+		os.Exit(2)
 	}
 
-	os.Exit(1)
+	if len(result.Failures) > 0 {
+		os.Exit(1)
+	}
 }
 
 type stringListFlag []string
@@ -4,23 +4,35 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"math"
 	"os"
+	"strings"
 	"time"
 
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	sopsv1beta1 "github.com/peak-scale/sops-operator/api/v1beta1"
+	"github.com/peak-scale/sops-operator/internal/audit"
 	"github.com/peak-scale/sops-operator/internal/controllers"
+	"github.com/peak-scale/sops-operator/internal/meta"
 	"github.com/peak-scale/sops-operator/internal/metrics"
+	"github.com/peak-scale/sops-operator/internal/tracing"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var (
@@ -32,28 +44,58 @@ func init() {
 	//+kubebuilder:scaffold:scheme
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(sopsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(sopsv1beta1.AddToScheme(scheme))
 }
 
 func main() {
-	var metricsAddr, secretErrorIntervalStr string
+	var metricsAddr, secretErrorIntervalStr, keySecretRefsIntervalStr, webhookCertDir, leaderElectionID string
 
-	var enableLeaderElection, enablePprof, enableStatus bool
+	var watchNamespaces, watchNamespaceSelector, shard, shardSelector string
+
+	var shardCount, shardIndex uint
+
+	var enableLeaderElection, enablePprof, enableStatus, enableWebhooks, restrictSecretCache bool
+
+	var enableGlobalSecrets, enableClusterProviders bool
+
+	var webhookPort int
 
 	var probeAddr string
 
 	flag.StringVar(&secretErrorIntervalStr, "secret-error-interval", "60s", "The requeued interval for failed kubernetes secret reconciliations")
+	flag.StringVar(&keySecretRefsIntervalStr, "key-secret-refs-interval", "5m", "The interval referenced key Secrets of providers are reloaded in")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":10080", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enablePprof, "enable-pprof", false, "Enables Pprof endpoint for profiling (not recommend in production)")
 	flag.BoolVar(&enableStatus, "enable-provider-status", true, "Add all available providers to the status of the SopsSecret resource")
+	flag.BoolVar(&restrictSecretCache, "restrict-secret-cache", false, "Only cache key Secrets and generated Secrets, other Secrets are read from the API server on demand")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Serve the conversion webhook between the API versions")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server binds to.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "", "The directory containing the webhook serving certificate (tls.crt, tls.key)")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated list of namespaces SopsSecrets, NamespacedSopsProviders and key Secrets are reconciled in, all namespaces when empty")
+	flag.StringVar(&watchNamespaceSelector, "watch-namespace-selector", "", "Label selector for the namespaces SopsSecrets, NamespacedSopsProviders and key Secrets are reconciled in")
+	flag.StringVar(&shard, "shard", "", "Only reconcile SopsSecrets labeled with this shard ("+meta.ShardLabel+"), other resources are left to the replica without shard")
+	flag.StringVar(&shardSelector, "shard-selector", "", "Label selector for the SopsSecrets reconciled by this replica, defaults to '!"+meta.ShardLabel+"' without --shard, leaving labeled SopsSecrets to their shard")
+	flag.UintVar(&shardCount, "shard-count", 0, "Number of hash based shards of SopsSecrets, disabled when 0")
+	flag.UintVar(&shardIndex, "shard-index", 0, "Index of the hash based shard of this replica, replicas with an index above 0 only reconcile SopsSecrets")
+	flag.BoolVar(&enableGlobalSecrets, "enable-global-secrets", true, "Reconcile GlobalSopsSecrets")
+	flag.BoolVar(&enableClusterProviders, "enable-cluster-providers", true, "Reconcile SopsProviders and use them for decryption")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "2e0ffcfb.peakscale.ch", "The name of the leader election lease, must be unique per operator instance")
 
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
+
+	var tracingOpts tracing.Options
+	tracingOpts.BindFlags(flag.CommandLine)
+
+	var auditOpts audit.Options
+	auditOpts.BindFlags(flag.CommandLine)
+
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
@@ -64,19 +106,109 @@ func main() {
 		os.Exit(1)
 	}
 
+	keySecretRefsInterval, err := time.ParseDuration(keySecretRefsIntervalStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid duration for --key-secret-refs-interval: %v\n", err)
+		os.Exit(1)
+	}
+
+	if enableGlobalSecrets && !enableClusterProviders {
+		fmt.Fprintln(os.Stderr, "--enable-global-secrets requires --enable-cluster-providers, GlobalSopsSecrets are only decrypted by SopsProviders")
+		os.Exit(1)
+	}
+
+	shardFilter, err := parseShard(shard, shardSelector, shardCount, shardIndex)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid sharding: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Replicas of a shard only reconcile SopsSecrets
+	shardOnly := shard != "" || shardIndex > 0
+
+	if shard != "" {
+		leaderElectionID += "-" + shard
+	}
+
+	if shardCount > 0 {
+		leaderElectionID += fmt.Sprintf("-%d", shardIndex)
+	}
+
+	namespaceFilter := controllers.NamespaceFilter{}
+
+	for ns := range strings.SplitSeq(watchNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaceFilter.Names = append(namespaceFilter.Names, ns)
+		}
+	}
+
+	if watchNamespaceSelector != "" {
+		namespaceFilter.Selector, err = labels.Parse(watchNamespaceSelector)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid label selector for --watch-namespace-selector: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	ctrlConfig := ctrl.Options{
 		Scheme:                  scheme,
 		Metrics:                 metricsserver.Options{BindAddress: metricsAddr},
 		HealthProbeBindAddress:  probeAddr,
 		LeaderElection:          enableLeaderElection,
 		LeaderElectionNamespace: os.Getenv("NAMESPACE"),
-		LeaderElectionID:        "2e0ffcfb.peakscale.ch",
+		LeaderElectionID:        leaderElectionID,
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				// Referenced documents are read on demand, instead of caching all ConfigMaps
+				DisableFor: []client.Object{&corev1.ConfigMap{}},
+			},
+		},
 	}
 
 	if enablePprof {
 		ctrlConfig.PprofBindAddress = ":8082"
 	}
 
+	if len(namespaceFilter.Names) > 0 {
+		// Namespaced objects are only cached in the watched namespaces
+		ctrlConfig.Cache.DefaultNamespaces = make(map[string]cache.Config, len(namespaceFilter.Names))
+		for _, ns := range namespaceFilter.Names {
+			ctrlConfig.Cache.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+
+	ctrlConfig.Cache.ByObject = map[client.Object]cache.ByObject{}
+
+	if restrictSecretCache {
+		ctrlConfig.Cache.ByObject[&corev1.Secret{}] = cache.ByObject{Label: meta.GeneratedSecretSelector()}
+	}
+
+	if shardFilter.Selector != nil {
+		// Only SopsSecrets of the shard are cached
+		ctrlConfig.Cache.ByObject[&sopsv1alpha1.SopsSecret{}] = cache.ByObject{Label: shardFilter.Selector}
+	}
+
+	if enableWebhooks {
+		ctrlConfig.WebhookServer = webhook.NewServer(webhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		})
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	auditLogger, closeAudit, err := audit.New(auditOpts, ctrl.Log.WithName("audit"))
+	if err != nil {
+		setupLog.Error(err, "unable to set up audit log")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrlConfig)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -85,45 +217,127 @@ func main() {
 
 	metricsRecorder := metrics.MustMakeRecorder()
 
+	mgrClient := mgr.GetClient()
+	keySecretCache := mgr.GetCache()
+
+	if len(namespaceFilter.Names) > 0 {
+		mgrClient = controllers.NewNamespacedClient(mgrClient, mgr.GetAPIReader(), namespaceFilter.Names)
+	}
+
+	if restrictSecretCache {
+		mgrClient = controllers.NewRestrictedSecretsClient(mgrClient, mgr.GetAPIReader())
+
+		// Key Secrets are cached separately, as the manager cache only
+		// contains generated secrets
+		keySecretCache, err = cache.New(mgr.GetConfig(), cache.Options{
+			Scheme:            mgr.GetScheme(),
+			Mapper:            mgr.GetRESTMapper(),
+			DefaultNamespaces: ctrlConfig.Cache.DefaultNamespaces,
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Secret{}: {Label: meta.KeySecretSelector()},
+			},
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create key secret cache")
+			os.Exit(1)
+		}
+
+		if err = mgr.Add(keySecretCache); err != nil {
+			setupLog.Error(err, "unable to add key secret cache")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controllers.SopsSecretReconciler{
-		Client:  mgr.GetClient(),
-		Log:     ctrl.Log.WithName("Controllers").WithName("SopsSecrets"),
-		Metrics: metricsRecorder,
-		Scheme:  mgr.GetScheme(),
+		Client:    mgrClient,
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("Controllers").WithName("SopsSecrets"),
+		Metrics:   metricsRecorder,
+		Audit:     auditLogger,
+		Scheme:    mgr.GetScheme(),
 	}).SetupWithManager(mgr, controllers.SopsSecretReconcilerConfig{
-		EnableStatus:          enableStatus,
-		FailedSecretsInterval: metav1.Duration{Duration: secretErrorInterval},
-		ControllerName:        "sopssecret",
+		EnableStatus:            enableStatus,
+		FailedSecretsInterval:   metav1.Duration{Duration: secretErrorInterval},
+		ControllerName:          "sopssecret",
+		DisableClusterProviders: !enableClusterProviders,
+		Namespaces:              namespaceFilter,
+		Shard:                   shardFilter,
+		RestrictSecretCache:     restrictSecretCache,
 	}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SopsSecret")
 		os.Exit(1)
 	}
 
-	if err = (&controllers.GlobalSopsSecretReconciler{
-		Client:  mgr.GetClient(),
-		Log:     ctrl.Log.WithName("Controllers").WithName("GlobalSopsSecrets"),
-		Metrics: metricsRecorder,
-		Scheme:  mgr.GetScheme(),
+	if !enableGlobalSecrets || shardOnly {
+		setupLog.Info("GlobalSopsSecrets disabled")
+	} else if err = (&controllers.GlobalSopsSecretReconciler{
+		Client:    mgrClient,
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("Controllers").WithName("GlobalSopsSecrets"),
+		Metrics:   metricsRecorder,
+		Audit:     auditLogger,
+		Scheme:    mgr.GetScheme(),
 	}).SetupWithManager(mgr, controllers.SopsSecretReconcilerConfig{
 		EnableStatus:          enableStatus,
 		FailedSecretsInterval: metav1.Duration{Duration: secretErrorInterval},
 		ControllerName:        "globalsopssecret",
+		RestrictSecretCache:   restrictSecretCache,
 	}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GlobalSopsSecret")
 		os.Exit(1)
 	}
 
-	if err = (&controllers.SopsProviderReconciler{
-		Client:  mgr.GetClient(),
-		Log:     ctrl.Log.WithName("Controllers").WithName("Providers"),
-		Metrics: metricsRecorder,
-		Scheme:  mgr.GetScheme(),
+	if !enableClusterProviders || shardOnly {
+		setupLog.Info("SopsProviders disabled")
+	} else if err = (&controllers.SopsProviderReconciler{
+		Client:                mgrClient,
+		APIReader:             mgr.GetAPIReader(),
+		Log:                   ctrl.Log.WithName("Controllers").WithName("Providers"),
+		Metrics:               metricsRecorder,
+		Scheme:                mgr.GetScheme(),
+		Namespaces:            namespaceFilter,
+		KeySecretRefsInterval: keySecretRefsInterval,
+		KeySecrets:            keySecretCache,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SopsProvider")
 		os.Exit(1)
 	}
+
+	if shardOnly {
+		setupLog.Info("NamespacedSopsProviders disabled")
+	} else if err = (&controllers.NamespacedSopsProviderReconciler{
+		Client:     mgrClient,
+		Log:        ctrl.Log.WithName("Controllers").WithName("NamespacedProviders"),
+		Metrics:    metricsRecorder,
+		Scheme:     mgr.GetScheme(),
+		Namespaces: namespaceFilter,
+		KeySecrets: keySecretCache,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespacedSopsProvider")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
+	if enableWebhooks {
+		// v1alpha1 is the conversion hub, the webhook converts all other versions from and to it
+		for _, hub := range []runtime.Object{
+			&sopsv1alpha1.SopsSecret{},
+			&sopsv1alpha1.GlobalSopsSecret{},
+			&sopsv1alpha1.SopsProvider{},
+			&sopsv1alpha1.NamespacedSopsProvider{},
+		} {
+			if err := ctrl.NewWebhookManagedBy(mgr, hub).Complete(); err != nil {
+				setupLog.Error(err, "unable to create conversion webhook", "type", fmt.Sprintf("%T", hub))
+				os.Exit(1)
+			}
+		}
+
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -136,8 +350,53 @@ func main() {
 
 	setupLog.Info("starting manager")
 
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
-		setupLog.Error(err, "problem running manager")
+	startErr := mgr.Start(ctx)
+
+	// Flush pending spans, the signal context is already canceled
+	if err := shutdownTracing(context.Background()); err != nil {
+		setupLog.Error(err, "unable to shut down tracing")
+	}
+
+	if err := closeAudit(); err != nil {
+		setupLog.Error(err, "unable to close audit log")
+	}
+
+	if startErr != nil {
+		setupLog.Error(startErr, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// Sharding of SopsSecrets, by the shard label or a label selector and by hash.
+func parseShard(shard, selector string, count, index uint) (filter controllers.ShardFilter, err error) {
+	if shard != "" && selector != "" {
+		return filter, fmt.Errorf("--shard and --shard-selector are mutually exclusive")
+	}
+
+	if count > 0 && index >= count {
+		return filter, fmt.Errorf("--shard-index %d must be lower than --shard-count %d", index, count)
+	}
+
+	if count == 0 && index > 0 {
+		return filter, fmt.Errorf("--shard-index requires --shard-count")
+	}
+
+	if count > math.MaxUint32 {
+		return filter, fmt.Errorf("--shard-count %d is too large", count)
+	}
+
+	filter.Count = uint32(count)
+	filter.Index = uint32(index)
+
+	switch {
+	case shard != "":
+		filter.Selector, err = labels.ValidatedSelectorFromSet(labels.Set{meta.ShardLabel: shard})
+	case selector != "":
+		filter.Selector, err = labels.Parse(selector)
+	default:
+		// SopsSecrets labeled with a shard are reconciled by the replicas of the shard
+		filter.Selector, err = labels.Parse("!" + meta.ShardLabel)
+	}
+
+	return filter, err
+}
@@ -0,0 +1,384 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	"github.com/peak-scale/sops-operator/internal/kubectl"
+	corev1 "k8s.io/api/core/v1"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const usage = `kubectl-sops works with SopsSecrets and GlobalSopsSecrets.
+
+Usage:
+  kubectl sops convert [SECRET] [-f FILE] [--global] [--encrypt]
+  kubectl sops providers [SOPSSECRET] [-f FILE] [--global]
+  kubectl sops explain [SOPSSECRET] [-f FILE] [--global]
+  kubectl sops migrate -f FILE [-f FILE...] [--keys PATH...]
+
+Commands:
+  convert     Convert a v1/Secret from the cluster or a file into a SopsSecret or GlobalSopsSecret
+  providers   Show the SopsProviders selecting a SopsSecret or GlobalSopsSecret
+  explain     Diagnose why a SopsSecret or GlobalSopsSecret can not be decrypted
+  migrate     Migrate isindir SopsSecrets and Flux SOPS decryption into SopsSecrets and SopsProviders
+
+Use "kubectl sops <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "convert":
+		err = runConvert(context.Background(), os.Args[2:])
+	case "providers":
+		err = runProviders(context.Background(), os.Args[2:])
+	case "explain":
+		err = runExplain(context.Background(), os.Args[2:])
+	case "migrate":
+		err = runMigrate(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "kubectl-sops: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "kubectl-sops: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Registers the kubectl compatible cluster selection flags.
+func clientFlags(flags *flag.FlagSet) *kubectl.ClientOptions {
+	opts := &kubectl.ClientOptions{}
+
+	flags.StringVar(&opts.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file.")
+	flags.StringVar(&opts.Context, "context", "", "The kubeconfig context to use.")
+	flags.StringVar(&opts.Namespace, "namespace", "", "The namespace to use.")
+	flags.StringVar(&opts.Namespace, "n", "", "The namespace to use (shorthand).")
+
+	return opts
+}
+
+func runConvert(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	clientOpts := clientFlags(flags)
+
+	var (
+		file, name, configPath, path string
+		global, encrypt              bool
+	)
+
+	flags.StringVar(&file, "f", "", "Read the Secret from a file, - for stdin, instead of the cluster.")
+	flags.StringVar(&name, "name", "", "Name of the SopsSecret. Defaults to the name of the Secret.")
+	flags.BoolVar(&global, "global", false, "Convert into a GlobalSopsSecret.")
+	flags.BoolVar(&encrypt, "encrypt", false, "Encrypt the SopsSecret with the keys of the matching .sops.yaml creation rule.")
+	flags.StringVar(&configPath, "sops-config", "", "Path to the .sops.yaml file. Discovered from --path when omitted.")
+	flags.StringVar(&path, "path", "", "Path matched against the .sops.yaml creation rules. Defaults to <name>.yaml.")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var secret *corev1.Secret
+
+	switch {
+	case file != "" && flags.NArg() == 0:
+		var err error
+
+		secret, err = readFile(file, kubectl.ReadSecret)
+		if err != nil {
+			return err
+		}
+
+		if clientOpts.Namespace != "" {
+			secret.Namespace = clientOpts.Namespace
+		}
+	case file == "" && flags.NArg() == 1:
+		c, namespace, err := kubectl.NewClient(*clientOpts)
+		if err != nil {
+			return err
+		}
+
+		secret = &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Name: flags.Arg(0), Namespace: namespace}, secret); err != nil {
+			return err
+		}
+	default:
+		return errors.New("either a Secret name or -f is required")
+	}
+
+	obj, err := kubectl.Convert(secret, kubectl.ConvertOptions{
+		Global: global,
+		Name:   name,
+	})
+	if err != nil {
+		return err
+	}
+
+	manifest, err := kubectl.Manifest(obj)
+	if err != nil {
+		return err
+	}
+
+	if encrypt {
+		if path == "" {
+			path = obj.GetName() + ".yaml"
+		}
+
+		if manifest, err = kubectl.Encrypt(manifest, configPath, path); err != nil {
+			return err
+		}
+	}
+
+	_, err = os.Stdout.Write(manifest)
+
+	return err
+}
+
+func runProviders(ctx context.Context, args []string) error {
+	c, obj, err := sopsSecretFromArgs(ctx, "providers", args)
+	if err != nil {
+		return err
+	}
+
+	providers, err := kubectl.MatchingProviders(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+
+	if len(providers) == 0 {
+		return fmt.Errorf("no SopsProvider selects %s %q", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName())
+	}
+
+	return kubectl.WriteProviders(os.Stdout, providers)
+}
+
+func runExplain(ctx context.Context, args []string) error {
+	c, obj, err := sopsSecretFromArgs(ctx, "explain", args)
+	if err != nil {
+		return err
+	}
+
+	explanation, err := kubectl.Explain(ctx, c, obj)
+	if err != nil {
+		return err
+	}
+
+	return explanation.Write(os.Stdout)
+}
+
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+
+	var (
+		files, keys      stringListFlag
+		configPath, path string
+	)
+
+	flags.Var(&files, "f", "Manifest file or directory of manifests to migrate, - for stdin. Can be provided multiple times.")
+	flags.Var(&keys, "keys", "Key file (*.agekey, *.asc, ...), directory of key files or Secret manifest to decrypt the manifests. Can be provided multiple times.")
+	flags.StringVar(&configPath, "sops-config", "", "Path to the .sops.yaml file. Discovered from the path of each manifest when omitted.")
+	flags.StringVar(&path, "path", "", "Path matched against the .sops.yaml creation rules for manifests read from stdin. Defaults to <name>.yaml.")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if len(files) == 0 || flags.NArg() > 0 {
+		return errors.New("manifests to migrate are required with -f")
+	}
+
+	sources := make([]kubectl.MigrationSource, 0, len(files))
+
+	for _, file := range files {
+		read, err := migrationSources(file, path)
+		if err != nil {
+			return err
+		}
+
+		sources = append(sources, read...)
+	}
+
+	dec, cleanup, err := decryptor.NewSOPSTempDecryptor()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	for _, key := range keys {
+		if err := dec.KeysFromPath(key); err != nil {
+			return err
+		}
+	}
+
+	migration, err := kubectl.Migrate(sources, kubectl.MigrateOptions{
+		Decryptor:  dec,
+		ConfigPath: configPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, note := range migration.Notes {
+		fmt.Fprintf(os.Stderr, "note: %s\n", note)
+	}
+
+	return migration.Write(os.Stdout)
+}
+
+// Reads a manifest file, all manifests in a directory, or stdin for -.
+func migrationSources(file, stdinPath string) ([]kubectl.MigrationSource, error) {
+	if file == "-" {
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+
+		return []kubectl.MigrationSource{{Path: stdinPath, Content: content}}, nil
+	}
+
+	sources := make([]kubectl.MigrationSource, 0)
+
+	err := filepath.WalkDir(file, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		// Files given explicitly are read independent of their extension
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+		default:
+			if path != file {
+				return nil
+			}
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		sources = append(sources, kubectl.MigrationSource{Path: path, Content: content})
+
+		return nil
+	})
+
+	return sources, err
+}
+
+// Parses the flags of commands working on a SopsSecret or GlobalSopsSecret,
+// which is read from the cluster or a file.
+func sopsSecretFromArgs(ctx context.Context, command string, args []string) (client.Client, api.SopsImplementation, error) {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	clientOpts := clientFlags(flags)
+
+	var (
+		file   string
+		global bool
+	)
+
+	flags.StringVar(&file, "f", "", "Read the SopsSecret or GlobalSopsSecret from a file, - for stdin, instead of the cluster.")
+	flags.BoolVar(&global, "global", false, "Get a GlobalSopsSecret instead of a SopsSecret from the cluster.")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	c, namespace, err := kubectl.NewClient(*clientOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case file != "" && flags.NArg() == 0:
+		obj, err := readFile(file, kubectl.ReadSopsSecret)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if _, ok := obj.(*sopsv1alpha1.SopsSecret); ok && obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+
+		return c, obj, nil
+	case file == "" && flags.NArg() == 1:
+		if global {
+			namespace = ""
+		}
+
+		obj, err := kubectl.GetSopsSecret(ctx, c, flags.Arg(0), namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return c, obj, nil
+	default:
+		return nil, nil, errors.New("either a SopsSecret name or -f is required")
+	}
+}
+
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+// Reads a manifest from a file, or stdin for -.
+func readFile[T any](file string, read func(io.Reader) (T, error)) (T, error) {
+	if file == "-" {
+		return read(os.Stdin)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+	defer f.Close()
+
+	return read(f)
+}
@@ -0,0 +1,60 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KeySecretReference references a Secret containing private keys explicitly,
+// instead of selecting it by labels
+// +kubebuilder:object:generate=true
+type KeySecretReference struct {
+	// Namespace of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Keys of the Secret data to load. All keys are loaded when empty
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// Matches returns true if the reference points to the given Secret.
+func (r *KeySecretReference) Matches(namespace, name string) bool {
+	return r.Namespace == namespace && r.Name == name
+}
+
+// Fetch reads the referenced Secret. The returned Secret only contains the
+// referenced keys in its data, missing keys are reported as error.
+func (r *KeySecretReference) Fetch(ctx context.Context, reader client.Reader) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: r.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get key secret %s/%s: %w", r.Namespace, r.Name, err)
+	}
+
+	if len(r.Keys) == 0 {
+		return secret, nil
+	}
+
+	data := make(map[string][]byte, len(r.Keys))
+
+	for _, key := range r.Keys {
+		value, ok := secret.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found in key secret %s/%s", key, r.Namespace, r.Name)
+		}
+
+		data[key] = value
+	}
+
+	secret.Data = data
+
+	return secret, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeySecretReferenceFetch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	c := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "sops-system"},
+		Data: map[string][]byte{
+			"team-a.agekey": []byte("a"),
+			"team-b.agekey": []byte("b"),
+		},
+	}).Build()
+
+	secret, err := (&KeySecretReference{Namespace: "sops-system", Name: "keys"}).Fetch(ctx, c)
+	require.NoError(t, err)
+	require.Len(t, secret.Data, 2)
+
+	secret, err = (&KeySecretReference{Namespace: "sops-system", Name: "keys", Keys: []string{"team-a.agekey"}}).Fetch(ctx, c)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"team-a.agekey": []byte("a")}, secret.Data)
+
+	_, err = (&KeySecretReference{Namespace: "sops-system", Name: "keys", Keys: []string{"missing.agekey"}}).Fetch(ctx, c)
+	require.ErrorContains(t, err, "missing.agekey")
+
+	_, err = (&KeySecretReference{Namespace: "default", Name: "keys"}).Fetch(ctx, c)
+	require.Error(t, err)
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/peak-scale/sops-operator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -64,6 +66,15 @@ func (s *NamespacedSelector) SingleMatch(
 		return true, nil
 	}
 
+	ctx, span := tracing.Start(ctx, "NamespacedSelector.SingleMatch",
+		attribute.String("object.namespace", obj.GetNamespace()),
+		attribute.String("object.name", obj.GetName()),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Bool("match", state))
+		tracing.End(span, err)
+	}()
+
 	if obj.GetNamespace() != "" {
 		// Get namespaces matching NamespaceSelector
 		matchingNamespaces, err := s.GetMatchingNamespaces(ctx, client)
@@ -84,6 +84,26 @@ func (in *Hckmskey) DeepCopy() *Hckmskey {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeySecretReference) DeepCopyInto(out *KeySecretReference) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySecretReference.
+func (in *KeySecretReference) DeepCopy() *KeySecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KeySecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Keygroup) DeepCopyInto(out *Keygroup) {
 	*out = *in
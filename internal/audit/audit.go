@@ -0,0 +1,239 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EventType is the type of an audit Event.
+type EventType string
+
+const (
+	// EventDecrypt is emitted for each decryption of a secret item.
+	EventDecrypt EventType = "decrypt"
+	// EventSecret is emitted for each write or delete of a generated Secret.
+	EventSecret EventType = "secret"
+)
+
+// Results of an audit Event.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Reference to a Kubernetes object.
+type Reference struct {
+	APIVersion string    `json:"apiVersion,omitempty"`
+	Kind       string    `json:"kind,omitempty"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid,omitempty"`
+}
+
+// ReferenceTo returns the reference to an object of the given kind.
+func ReferenceTo(obj client.Object, gvk schema.GroupVersionKind) Reference {
+	return Reference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	}
+}
+
+// Event is a single audit record. It never contains decrypted values.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+	// Operation on the generated Secret (create, update, delete).
+	Operation string `json:"operation,omitempty"`
+	// Result is either success or failure.
+	Result string `json:"result"`
+	// Error of failed events.
+	Error string `json:"error,omitempty"`
+	// Object is the SopsSecret or GlobalSopsSecret.
+	Object Reference `json:"object"`
+	// Item is the name of the secret item.
+	Item string `json:"item,omitempty"`
+	// Providers are the SopsProviders selecting the object.
+	Providers []string `json:"providers,omitempty"`
+	// Recipients are the keys which decrypted the data keys.
+	Recipients []decryptor.Recipient `json:"recipients,omitempty"`
+	// Secret is the generated Secret.
+	Secret *Reference `json:"secret,omitempty"`
+	// ContentHash is the HMAC-SHA256 of the checksum of the desired state of
+	// the generated Secret, keyed with the hash key of the Logger. Unlike the
+	// checksum, it can not be brute-forced without the key.
+	ContentHash string `json:"contentHash,omitempty"`
+}
+
+// Sink receives the audit events.
+type Sink interface {
+	Write(ctx context.Context, event Event) error
+}
+
+// Logger emits audit events to sinks. A nil Logger discards all events.
+type Logger struct {
+	log   logr.Logger
+	sinks []Sink
+	now   func() time.Time
+	// Key of the content hashes
+	hashKey []byte
+}
+
+// NewLogger returns a Logger emitting the events to the sinks. Failures of the
+// sinks are logged with log. Content hashes are keyed with a random key, use
+// WithHashKey for hashes comparable across restarts.
+func NewLogger(log logr.Logger, sinks ...Sink) *Logger {
+	hashKey := make([]byte, sha256.Size)
+	_, _ = rand.Read(hashKey)
+
+	return &Logger{
+		log:     log,
+		sinks:   sinks,
+		now:     time.Now,
+		hashKey: hashKey,
+	}
+}
+
+// WithHashKey sets the key of the content hashes.
+func (l *Logger) WithHashKey(key []byte) *Logger {
+	l.hashKey = key
+
+	return l
+}
+
+// Keyed hash of the checksum of a generated Secret.
+func (l *Logger) contentHash(checksum string) string {
+	if checksum == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, l.hashKey)
+	mac.Write([]byte(checksum))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Emit sends the event to all sinks. The time is set when empty. Failing sinks
+// do not fail the reconciliation, they are logged.
+func (l *Logger) Emit(ctx context.Context, event Event) {
+	if l == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = l.now().UTC()
+	}
+
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, event); err != nil {
+			l.log.Error(err, "failed to write audit event", "type", event.Type, "object", event.Object.Name)
+		}
+	}
+}
+
+// For returns a logger for the events of an object of the given kind, selected
+// by providers.
+func (l *Logger) For(obj client.Object, gvk schema.GroupVersionKind, providers []string) *ObjectLogger {
+	if l == nil {
+		return nil
+	}
+
+	return &ObjectLogger{
+		logger:    l,
+		object:    ReferenceTo(obj, gvk),
+		providers: providers,
+	}
+}
+
+// ObjectLogger emits the audit events of a SopsSecret or GlobalSopsSecret. A
+// nil ObjectLogger discards all events.
+type ObjectLogger struct {
+	logger    *Logger
+	object    Reference
+	providers []string
+}
+
+// Decryption emits the event of the decryption of a secret item.
+func (o *ObjectLogger) Decryption(ctx context.Context, item string, recipients []decryptor.Recipient, err error) {
+	if o == nil {
+		return
+	}
+
+	o.logger.Emit(ctx, o.event(EventDecrypt, item, recipients, err))
+}
+
+// SecretWritten emits the event of a write of a generated Secret, with the
+// keyed hash of its checksum.
+func (o *ObjectLogger) SecretWritten(
+	ctx context.Context,
+	item string,
+	operation string,
+	secret client.Object,
+	checksum string,
+	recipients []decryptor.Recipient,
+) {
+	if o == nil {
+		return
+	}
+
+	ref := ReferenceTo(secret, corev1.SchemeGroupVersion.WithKind("Secret"))
+
+	event := o.event(EventSecret, item, recipients, nil)
+	event.Operation = operation
+	event.Secret = &ref
+	event.ContentHash = o.logger.contentHash(checksum)
+
+	o.logger.Emit(ctx, event)
+}
+
+// SecretRemoved emits the event of the removal of a generated Secret.
+func (o *ObjectLogger) SecretRemoved(ctx context.Context, operation string, namespace string, name string, err error) {
+	if o == nil {
+		return
+	}
+
+	event := o.event(EventSecret, "", nil, err)
+	event.Operation = operation
+	event.Secret = &Reference{
+		APIVersion: corev1.SchemeGroupVersion.String(),
+		Kind:       "Secret",
+		Namespace:  namespace,
+		Name:       name,
+	}
+
+	o.logger.Emit(ctx, event)
+}
+
+func (o *ObjectLogger) event(eventType EventType, item string, recipients []decryptor.Recipient, err error) Event {
+	event := Event{
+		Type:       eventType,
+		Result:     ResultSuccess,
+		Object:     o.object,
+		Item:       item,
+		Providers:  o.providers,
+		Recipients: recipients,
+	}
+
+	if err != nil {
+		event.Result = ResultFailure
+		event.Error = err.Error()
+	}
+
+	return event
+}
@@ -0,0 +1,211 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type failingSink struct{}
+
+func (failingSink) Write(context.Context, Event) error {
+	return errors.New("unavailable")
+}
+
+func decodeEvents(t *testing.T, r io.Reader) []map[string]any {
+	t.Helper()
+
+	var events []map[string]any
+
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var event map[string]any
+		require.NoError(t, decoder.Decode(&event))
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func TestObjectLogger(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	logger := NewLogger(logr.Discard(), NewWriterSink(&out), failingSink{}).WithHashKey([]byte("audit-key"))
+	logger.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	sum := sha256.Sum256([]byte("password"))
+	checksum := hex.EncodeToString(sum[:])
+
+	origin := &sopsv1alpha1.SopsSecret{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "tenant", UID: "1234"}}
+	target := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app-db", Namespace: "tenant", UID: "5678"}}
+	recipients := []decryptor.Recipient{{Type: decryptor.KeyTypeAge, ID: "age1example"}}
+
+	auditor := logger.For(origin, sopsv1alpha1.GroupVersion.WithKind("SopsSecret"), []string{"platform"})
+	auditor.Decryption(context.Background(), "app-db", recipients, nil)
+	auditor.SecretWritten(context.Background(), "app-db", "create", target, checksum, recipients)
+	auditor.Decryption(context.Background(), "broken", nil, errors.New("cannot get sops data key"))
+	auditor.SecretRemoved(context.Background(), "delete", "tenant", "old", nil)
+
+	events := decodeEvents(t, &out)
+	require.Len(t, events, 4)
+
+	require.Equal(t, map[string]any{
+		"time":   "2025-01-02T03:04:05Z",
+		"type":   "decrypt",
+		"result": "success",
+		"object": map[string]any{
+			"apiVersion": "addons.projectcapsule.dev/v1alpha1",
+			"kind":       "SopsSecret",
+			"namespace":  "tenant",
+			"name":       "app",
+			"uid":        "1234",
+		},
+		"item":       "app-db",
+		"providers":  []any{"platform"},
+		"recipients": []any{map[string]any{"type": "age", "id": "age1example"}},
+	}, events[0])
+
+	require.Equal(t, "secret", events[1]["type"])
+	require.Equal(t, "create", events[1]["operation"])
+	// The checksum is keyed, it can not be brute-forced without the key
+	mac := hmac.New(sha256.New, []byte("audit-key"))
+	mac.Write([]byte(checksum))
+	require.Equal(t, hex.EncodeToString(mac.Sum(nil)), events[1]["contentHash"])
+	require.NotEqual(t, checksum, events[1]["contentHash"])
+	require.Equal(t, map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"namespace":  "tenant",
+		"name":       "app-db",
+		"uid":        "5678",
+	}, events[1]["secret"])
+
+	require.Equal(t, "failure", events[2]["result"])
+	require.Equal(t, "cannot get sops data key", events[2]["error"])
+
+	require.Equal(t, "delete", events[3]["operation"])
+	require.Equal(t, "old", events[3]["secret"].(map[string]any)["name"])
+}
+
+func TestNilLogger(t *testing.T) {
+	t.Parallel()
+
+	var logger *Logger
+
+	auditor := logger.For(&sopsv1alpha1.GlobalSopsSecret{}, sopsv1alpha1.GroupVersion.WithKind("GlobalSopsSecret"), nil)
+	require.Nil(t, auditor)
+
+	// Discarded without panic
+	logger.Emit(context.Background(), Event{Type: EventDecrypt})
+	auditor.Decryption(context.Background(), "item", nil, nil)
+	auditor.SecretWritten(context.Background(), "item", "create", &corev1.Secret{}, "", nil)
+	auditor.SecretRemoved(context.Background(), "delete", "default", "item", nil)
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan Event, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		received <- event
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, server.Client())
+	require.NoError(t, sink.Write(context.Background(), Event{Type: EventDecrypt, Item: "app"}))
+	require.Equal(t, "app", (<-received).Item)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	err := NewWebhookSink(failing.URL, failing.Client()).Write(context.Background(), Event{Type: EventDecrypt})
+	require.ErrorContains(t, err, "500")
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	logger, closer, err := New(Options{}, logr.Discard())
+	require.NoError(t, err)
+	require.Nil(t, logger)
+	require.NoError(t, closer())
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o600))
+
+	logger, closer, err = New(Options{Path: path}, logr.Discard())
+	require.NoError(t, err)
+	require.NotNil(t, logger)
+
+	logger.Emit(context.Background(), Event{Type: EventDecrypt, Item: "app"})
+	require.NoError(t, closer())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2, "events are appended")
+	require.Contains(t, lines[1], `"item":"app"`)
+
+	_, _, err = New(Options{Path: filepath.Join(t.TempDir(), "missing", "audit.log")}, logr.Discard())
+	require.Error(t, err)
+
+	// Content hashes are comparable across loggers with the same key
+	keyFile := filepath.Join(t.TempDir(), "hash-key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("audit-key\n"), 0o600))
+
+	logger, closer, err = New(Options{Path: "-", HashKeyFile: keyFile}, logr.Discard())
+	require.NoError(t, err)
+	require.NoError(t, closer())
+	require.Equal(t,
+		NewLogger(logr.Discard()).WithHashKey([]byte("audit-key")).contentHash("abcd"),
+		logger.contentHash("abcd"),
+	)
+	require.NotEqual(t, NewLogger(logr.Discard()).contentHash("abcd"), logger.contentHash("abcd"))
+
+	_, _, err = New(Options{Path: "-", HashKeyFile: filepath.Join(t.TempDir(), "missing")}, logr.Discard())
+	require.Error(t, err)
+}
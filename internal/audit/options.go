@@ -0,0 +1,82 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+)
+
+// Options configures the audit sinks.
+type Options struct {
+	// Path of the file the events are appended to, "-" for stdout. The JSON
+	// lines sink is disabled when empty.
+	Path string
+	// WebhookURL the events are posted to. The webhook sink is disabled when
+	// empty.
+	WebhookURL string
+	// HashKeyFile contains the key of the content hashes. A random key is used
+	// when empty.
+	HashKeyFile string
+}
+
+// BindFlags binds the audit options to flags.
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Path, "audit-log", "", "The file audit events are appended to as JSON lines, '-' for stdout. Disabled when empty.")
+	fs.StringVar(&o.WebhookURL, "audit-webhook-url", "", "The URL audit events are posted to. Disabled when empty.")
+	fs.StringVar(&o.HashKeyFile, "audit-hash-key-file", "", "The file with the key content hashes of audit events are keyed with. A random key is used when empty, the hashes then change with each restart.")
+}
+
+// New returns a Logger for the configured sinks, nil if none is configured.
+// The returned function closes the sinks.
+func New(opts Options, log logr.Logger) (*Logger, func() error, error) {
+	var sinks []Sink
+
+	closer := func() error { return nil }
+
+	switch opts.Path {
+	case "":
+	case "-":
+		sinks = append(sinks, NewWriterSink(os.Stdout))
+	default:
+		sink, file, err := NewFileSink(opts.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sinks = append(sinks, sink)
+		closer = file.Close
+	}
+
+	if opts.WebhookURL != "" {
+		sinks = append(sinks, NewWebhookSink(opts.WebhookURL, nil))
+	}
+
+	if len(sinks) == 0 {
+		return nil, closer, nil
+	}
+
+	logger := NewLogger(log, sinks...)
+
+	if opts.HashKeyFile != "" {
+		key, err := os.ReadFile(opts.HashKeyFile)
+		key = bytes.TrimSpace(key)
+		if err != nil {
+			return nil, nil, errors.Join(err, closer())
+		}
+
+		if len(key) == 0 {
+			return nil, nil, errors.Join(fmt.Errorf("audit hash key file %q is empty", opts.HashKeyFile), closer())
+		}
+
+		logger.WithHashKey(key)
+	}
+
+	return logger, closer, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewRestrictedSecretsClient returns a client for the restricted secret cache
+// mode, where only key Secrets and generated secrets are cached. Secrets
+// missing in the cache, like referenced documents or secrets to merge into,
+// are read with the reader.
+func NewRestrictedSecretsClient(c client.Client, reader client.Reader) client.Client {
+	return &restrictedSecretsClient{Client: c, reader: reader}
+}
+
+type restrictedSecretsClient struct {
+	client.Client
+
+	reader client.Reader
+}
+
+func (c *restrictedSecretsClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+
+	if _, ok := obj.(*corev1.Secret); ok && apierrors.IsNotFound(err) {
+		return c.reader.Get(ctx, key, obj, opts...)
+	}
+
+	return err
+}
+
+// NewNamespacedClient returns a client for instances watching only the given
+// namespaces, where namespaced objects are only cached in these namespaces.
+// Objects in other namespaces, like the targets of GlobalSopsSecrets or key
+// Secrets of providers for tenant namespaces, are read with the reader.
+func NewNamespacedClient(c client.Client, reader client.Reader, namespaces []string) client.Client {
+	return &namespacedClient{Client: c, reader: reader, namespaces: namespaces}
+}
+
+type namespacedClient struct {
+	client.Client
+
+	reader     client.Reader
+	namespaces []string
+}
+
+func (c *namespacedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if !c.cached(key.Namespace) {
+		return c.reader.Get(ctx, key, obj, opts...)
+	}
+
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *namespacedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	if !c.cached(listOpts.Namespace) {
+		return c.reader.List(ctx, list, opts...)
+	}
+
+	return c.Client.List(ctx, list, opts...)
+}
+
+// Cluster-scoped objects and lists across all namespaces are served by the
+// cache.
+func (c *namespacedClient) cached(namespace string) bool {
+	return namespace == "" || slices.Contains(c.namespaces, namespace)
+}
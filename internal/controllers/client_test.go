@@ -0,0 +1,71 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestrictedSecretsClient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	uncached := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "document", Namespace: "default"}}
+	config := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}}
+
+	cached := fake.NewClientBuilder().Build()
+	reader := fake.NewClientBuilder().WithObjects(uncached, config).Build()
+
+	c := NewRestrictedSecretsClient(cached, reader)
+
+	// Secrets missing in the cache are read from the API server
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(uncached), &corev1.Secret{}))
+
+	err := c.Get(ctx, client.ObjectKey{Name: "missing", Namespace: "default"}, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+
+	// Other kinds are only read from the cache
+	err = c.Get(ctx, client.ObjectKeyFromObject(config), &corev1.ConfigMap{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestNamespacedClient(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	watched := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "watched", Namespace: "solar-prod"}}
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "wind-prod"}}
+
+	cached := fake.NewClientBuilder().WithObjects(watched).Build()
+	reader := fake.NewClientBuilder().WithObjects(other).Build()
+
+	c := NewNamespacedClient(cached, reader, []string{"solar-prod"})
+
+	// Objects in the watched namespaces are read from the cache
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(watched), &corev1.Secret{}))
+
+	// Objects in other namespaces are read from the API server
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(other), &corev1.Secret{}))
+
+	list := &corev1.SecretList{}
+	require.NoError(t, c.List(ctx, list, client.InNamespace("wind-prod")))
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "other", list.Items[0].Name)
+
+	// Lists across all namespaces are served by the cache
+	require.NoError(t, c.List(ctx, list))
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "watched", list.Items[0].Name)
+}
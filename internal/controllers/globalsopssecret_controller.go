@@ -11,9 +11,12 @@ import (
 	"github.com/go-logr/logr"
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
 	errs "github.com/peak-scale/sops-operator/internal/api/errors"
+	"github.com/peak-scale/sops-operator/internal/audit"
 	"github.com/peak-scale/sops-operator/internal/meta"
 	"github.com/peak-scale/sops-operator/internal/metrics"
+	"github.com/peak-scale/sops-operator/internal/tracing"
 	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,11 +37,14 @@ import (
 type GlobalSopsSecretReconciler struct {
 	client.Client
 
-	Metrics  *metrics.Recorder
-	Log      logr.Logger
-	Recorder record.EventRecorder
-	Scheme   *runtime.Scheme
-	Config   SopsSecretReconcilerConfig
+	// Reads referenced key Secrets, bypassing the cache
+	APIReader client.Reader
+	Metrics   *metrics.Recorder
+	Audit     *audit.Logger
+	Log       logr.Logger
+	Recorder  record.EventRecorder
+	Scheme    *runtime.Scheme
+	Config    SopsSecretReconcilerConfig
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -123,10 +129,17 @@ func (r *GlobalSopsSecretReconciler) SetupWithManager(mgr ctrl.Manager, cfg Sops
 func (r *GlobalSopsSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("Request.Name", req.Name)
 
+	ctx, span := tracing.Start(ctx, "GlobalSopsSecretReconciler.Reconcile", attribute.String("name", req.Name))
+	defer func() { tracing.End(span, err) }()
+
 	instance := &sopsv1alpha1.GlobalSopsSecret{}
 
 	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
 		if apierrors.IsNotFound(err) {
+			// The object is not populated when not found
+			instance.SetName(req.Name)
+			instance.SetNamespace(req.Namespace)
+
 			r.Metrics.DeleteGlobalSecret(instance)
 			log.V(5).Info("Request object not found, could have been deleted after reconcile request")
 
@@ -138,6 +151,20 @@ func (r *GlobalSopsSecretReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return reconcile.Result{}, nil
 	}
 
+	// Merged secrets are not owned, remove the merged keys before releasing the object
+	if !instance.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, finalizeMergedSecrets(ctx, r.Client, instance, &instance.Status)
+	}
+
+	items := make([]*sopsv1alpha1.SopsSecretItem, 0, len(instance.Spec.Secrets))
+	for _, sec := range instance.Spec.Secrets {
+		items = append(items, &sec.SopsSecretItem)
+	}
+
+	if err := reconcileMergeFinalizer(ctx, r.Client, instance, &instance.Status, items); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	reconcileErr := r.reconcile(
 		ctx,
 		log,
@@ -178,7 +205,9 @@ func (r *GlobalSopsSecretReconciler) reconcile(
 	// Load Decryption Provider (Keys)
 	log.V(5).Info("loading secrets provider")
 
-	sopsFormat, provider, cleanup, err := fetchDecryptionProviders(ctx, r.Client, log, r.Config, &secret.Status, secret)
+	sopsFormat, provider, providers, cleanup, err := fetchDecryptionProviders(ctx, r.Client, r.APIReader, log, r.Config, r.Metrics, &secret.Status, secret)
+
+	auditor := r.Audit.For(secret, sopsv1alpha1.GroupVersion.WithKind("GlobalSopsSecret"), providers)
 
 	defer func() {
 		if cleanup != nil {
@@ -191,6 +220,9 @@ func (r *GlobalSopsSecretReconciler) reconcile(
 		return cleanupSecrets(
 			ctx,
 			r.Client,
+			r.Metrics,
+			auditor,
+			secret,
 			&secret.Status,
 		)
 	}
@@ -198,21 +230,26 @@ func (r *GlobalSopsSecretReconciler) reconcile(
 	// Iterate over Secrets
 	selectedSecrets := make(map[string]bool)
 
+	drifted := make([]string, 0)
+
 	failed := false
 
 	for _, sec := range secret.Spec.Secrets {
 		slog := log.WithValues("secret", sec.Name)
 
 		// Reconcile Secret
-		target, serr := reconcileSecret(
+		target, outcome, serr := reconcileSecret(
 			ctx,
 			r.Client,
 			slog,
+			auditor,
 			sopsFormat,
 			provider,
 			&sec.SopsSecretItem,
 			sec.Namespace,
 			secret.Spec.Metadata,
+			secret.Spec.DriftPolicy,
+			r.Config.RestrictSecretCache,
 		)
 
 		selectedSecrets[target.Name+"/"+target.Namespace] = true
@@ -220,37 +257,55 @@ func (r *GlobalSopsSecretReconciler) reconcile(
 		if serr != nil {
 			failed = true
 
-			secret.Status.UpdateInstance(
-				meta.NewNotReadySecretStatusCondition(target, serr.Error()),
-			)
+			status := meta.NewNotReadySecretStatusCondition(target, serr.Error())
+			status.Mode = sec.Mode.OrDefault()
+
+			secret.Status.UpdateInstance(status)
 
 			continue
 		}
 
-		secret.Status.UpdateInstance(
-			meta.NewReadySecretStatusCondition(target),
-		)
+		if outcome.Operation != "" {
+			r.Metrics.RecordSecretOperation(outcome.Operation)
+			auditor.SecretWritten(ctx, sec.Name, outcome.Operation, target, outcome.Checksum, outcome.Recipients)
+		}
+
+		if len(outcome.Drift) > 0 {
+			drifted = append(drifted, target.Namespace+"/"+target.Name)
+		}
+
+		status := meta.NewReadySecretStatusCondition(target)
+		status.Mode = sec.Mode.OrDefault()
+		status.Conflicts = outcome.Conflicts
+
+		secret.Status.UpdateInstance(status)
 	}
 
+	secret.Status.Conditions.UpdateConditionByType(meta.NewDriftedCondition(
+		secret,
+		secret.Status.Conditions,
+		drifted,
+		secret.Spec.DriftPolicy.OrDefault() != sopsv1alpha1.DriftPolicyReport,
+	))
+
 	// Lifecycle Secrets
 	for _, sec := range secret.Status.Secrets {
 		if _, ok := selectedSecrets[sec.Name+"/"+sec.Namespace]; !ok {
 			log.V(7).Info("garbage collection", "secret", sec.Name)
 
-			err := r.Delete(ctx, &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      sec.Name,
-					Namespace: sec.Namespace,
-				},
-			})
-			if err != nil && !apierrors.IsNotFound(err) {
+			if err := removeSecret(ctx, r.Client, secret, sec); err != nil {
 				failed = true
 
+				auditor.SecretRemoved(ctx, removeOperation(sec), sec.Namespace, sec.Name, err)
+
 				log.Error(err, "error removing secret")
 
 				continue
 			}
 
+			r.Metrics.RecordSecretOperation(removeOperation(sec))
+			auditor.SecretRemoved(ctx, removeOperation(sec), sec.Namespace, sec.Name, nil)
+
 			// Remove Instance
 			secret.Status.RemoveInstance(&sopsv1alpha1.SopsSecretItemStatus{
 				Name:      sec.Name,
@@ -0,0 +1,100 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// keySecretWatch watches key Secrets once the first provider selects key
+// Secrets by labels. Until then Secrets are neither listed nor watched for
+// providers, which allows providers to only reference their key Secrets
+// without access to all Secrets.
+type keySecretWatch struct {
+	mu      sync.Mutex
+	started bool
+	watch   func() error
+}
+
+func newKeySecretWatch(keySecrets cache.Cache, c controller.Controller, mapFunc handler.MapFunc) *keySecretWatch {
+	return &keySecretWatch{
+		watch: func() error {
+			return c.Watch(source.Kind[client.Object](
+				keySecrets,
+				&corev1.Secret{},
+				handler.EnqueueRequestsFromMapFunc(mapFunc),
+				keySecretPredicate(),
+			))
+		},
+	}
+}
+
+// Start the watch, if not started yet.
+func (w *keySecretWatch) start(log logr.Logger) error {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.started {
+		return nil
+	}
+
+	if err := w.watch(); err != nil {
+		return err
+	}
+
+	log.V(4).Info("watching key secrets")
+
+	w.started = true
+
+	return nil
+}
+
+// Only events of key Secrets are relevant for providers.
+func keySecretPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return meta.IsKeySecret(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return meta.IsKeySecret(e.ObjectOld) || meta.IsKeySecret(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return meta.IsKeySecret(e.Object)
+		},
+	}
+}
+
+// List the Secrets labeled as key Secrets, the candidates for the key
+// selectors of providers.
+func listKeySecrets(ctx context.Context, c client.Reader, log logr.Logger, opts ...client.ListOption) ([]*corev1.Secret, error) {
+	secretList := &corev1.SecretList{}
+	if err := c.List(ctx, secretList, append(opts, client.MatchingLabelsSelector{Selector: meta.KeySecretSelector()})...); err != nil {
+		log.Error(err, "Failed to list secrets")
+
+		return nil, err
+	}
+
+	secrets := make([]*corev1.Secret, 0, len(secretList.Items))
+	for i := range secretList.Items {
+		secrets = append(secrets, &secretList.Items[i])
+	}
+
+	return secrets, nil
+}
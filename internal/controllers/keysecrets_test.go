@@ -0,0 +1,101 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/go-logr/logr"
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/peak-scale/sops-operator/internal/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestKeySecretWatchStartsOnce(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	failing := true
+
+	w := &keySecretWatch{watch: func() error {
+		calls++
+
+		if failing {
+			return errors.New("cache not ready")
+		}
+
+		return nil
+	}}
+
+	require.Error(t, w.start(logr.Discard()))
+
+	failing = false
+
+	require.NoError(t, w.start(logr.Discard()))
+	require.NoError(t, w.start(logr.Discard()))
+	require.Equal(t, 2, calls)
+
+	var unset *keySecretWatch
+	require.NoError(t, unset.start(logr.Discard()))
+}
+
+func TestSopsProviderReferencesOnly(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, sopsv1alpha1.AddToScheme(scheme))
+
+	keys := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "sops-system", UID: "keys-uid"},
+		Data:       map[string][]byte{"ci.agekey": []byte("invalid")},
+	}
+
+	// Secrets must neither be listed nor watched for referenced key Secrets
+	c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*corev1.SecretList); ok {
+				return errors.New("secrets listed")
+			}
+
+			return c.List(ctx, list, opts...)
+		},
+	}).Build()
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(keys).Build()
+
+	r := &SopsProviderReconciler{
+		Client:    c,
+		APIReader: reader,
+		Metrics:   metrics.NewRecorder(),
+		keySecrets: &keySecretWatch{watch: func() error {
+			return errors.New("secrets watched")
+		}},
+	}
+
+	provider := &sopsv1alpha1.SopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "refs"},
+		Spec: sopsv1alpha1.SopsProviderSpec{
+			KeySecretRefs: []*api.KeySecretReference{{Namespace: "sops-system", Name: "keys"}},
+		},
+	}
+
+	// The invalid key is reported on the referenced Secret
+	require.ErrorContains(t, r.reconcile(ctx, logr.Discard(), provider), "failed loading secret(s)")
+	require.Len(t, provider.Status.Providers, 1)
+	require.Equal(t, "keys", provider.Status.Providers[0].Name)
+}
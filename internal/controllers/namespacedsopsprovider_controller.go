@@ -0,0 +1,216 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/metrics"
+	"github.com/peak-scale/sops-operator/internal/tracing"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NamespacedSopsProviderReconciler reconciles a NamespacedSopsProvider object.
+type NamespacedSopsProviderReconciler struct {
+	client.Client
+
+	Metrics  *metrics.Recorder
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	Scheme   *runtime.Scheme
+	// Namespaces NamespacedSopsProviders are reconciled in
+	Namespaces NamespaceFilter
+	// Cache of key Secrets, the manager cache when not set
+	KeySecrets cache.Cache
+
+	keySecrets *keySecretWatch
+}
+
+func (r *NamespacedSopsProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	bld := ctrl.NewControllerManagedBy(mgr).
+		For(&sopsv1alpha1.NamespacedSopsProvider{}, builder.WithPredicates(primaryResourcePredicate()))
+
+	bld = r.Namespaces.watch(bld, func(ctx context.Context, namespace string) []reconcile.Request {
+		return r.providerRequests(ctx, client.InNamespace(namespace))
+	})
+
+	c, err := bld.Build(r)
+	if err != nil {
+		return err
+	}
+
+	if r.KeySecrets == nil {
+		r.KeySecrets = mgr.GetCache()
+	}
+
+	r.keySecrets = newKeySecretWatch(r.KeySecrets, c, func(ctx context.Context, obj client.Object) []reconcile.Request {
+		// Key Secrets are only selected by providers in their namespace
+		return r.providerRequests(ctx, client.InNamespace(obj.GetNamespace()))
+	})
+
+	return nil
+}
+
+// Requests for the NamespacedSopsProviders listed with opts.
+func (r *NamespacedSopsProviderReconciler) providerRequests(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	var list sopsv1alpha1.NamespacedSopsProviderList
+	if err := r.Client.List(ctx, &list, opts...); err != nil {
+		r.Log.Error(err, "unable to list NamespacedSopsProvider objects")
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, sp := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      sp.Name,
+				Namespace: sp.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
+
+func (r *NamespacedSopsProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	log := r.Log.WithValues("Request.Name", req.Name, "Request.Namespace", req.Namespace)
+
+	ctx, span := tracing.Start(ctx, "NamespacedSopsProviderReconciler.Reconcile",
+		attribute.String("namespace", req.Namespace),
+		attribute.String("name", req.Name),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	selected, err := r.Namespaces.Matches(ctx, r.Client, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !selected {
+		log.V(5).Info("namespace not selected, skipping")
+
+		return ctrl.Result{}, nil
+	}
+
+	instance := &sopsv1alpha1.NamespacedSopsProvider{}
+
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Cleanup Metrics, the object is not populated when not found
+			instance.SetName(req.Name)
+			instance.SetNamespace(req.Namespace)
+
+			r.Metrics.DeleteNamespacedProvider(instance)
+
+			log.V(5).Info("Request object not found, could have been deleted after reconcile request")
+
+			return reconcile.Result{}, nil
+		}
+
+		log.Error(err, "error reading the object")
+
+		return reconcile.Result{}, nil
+	}
+
+	reconcileErr := r.reconcile(ctx, log, instance)
+
+	defer func() {
+		r.Metrics.RecordNamespacedProviderCondition(instance)
+
+		if statusErr := r.updateStatus(ctx, reconcileErr, instance); statusErr != nil {
+			statusErr = fmt.Errorf("cannot update NamespacedSopsProvider status: %w", statusErr)
+
+			if err == nil {
+				err = statusErr
+			} else {
+				err = errors.Join(err, statusErr)
+			}
+		}
+	}()
+
+	if reconcileErr != nil {
+		return ctrl.Result{}, reconcileErr
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *NamespacedSopsProviderReconciler) reconcile(
+	ctx context.Context,
+	log logr.Logger,
+	provider *sopsv1alpha1.NamespacedSopsProvider,
+) (err error) {
+	selectedSecrets := make(map[string]*corev1.Secret)
+
+	// Key Secrets are only listed for providers selecting them by labels
+	if len(provider.Spec.KeySelectors) > 0 {
+		if lerr := r.keySecrets.start(log); lerr != nil {
+			return lerr
+		}
+
+		candidates, lerr := listKeySecrets(ctx, r.KeySecrets, log, client.InNamespace(provider.Namespace))
+		if lerr != nil {
+			return lerr
+		}
+
+		selectedSecrets, err = provider.SelectKeySecrets(candidates)
+	}
+
+	if lerr := loadKeySecrets(ctx, log, r.Metrics, provider.ProviderName(), &provider.Status, selectedSecrets); lerr != nil {
+		return lerr
+	}
+
+	return err
+}
+
+func (r *NamespacedSopsProviderReconciler) updateStatus(
+	ctx context.Context,
+	reconcileError error,
+	instance *sopsv1alpha1.NamespacedSopsProvider,
+) (err error) {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		latest := &sopsv1alpha1.NamespacedSopsProvider{}
+		if err = r.Get(ctx, types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}, latest); err != nil {
+			return err
+		}
+
+		latest.Status = instance.Status
+		latest.Status.ObservedGeneration = instance.GetGeneration()
+
+		readyCondition := capmeta.NewReadyCondition(latest)
+		readyCondition.ObservedGeneration = instance.GetGeneration()
+		readyCondition.Status = metav1.ConditionTrue
+		readyCondition.Reason = capmeta.SucceededReason
+		readyCondition.Message = "Secrets Decrypted"
+
+		if reconcileError != nil {
+			readyCondition.Message = reconcileError.Error()
+			readyCondition.Status = metav1.ConditionFalse
+			readyCondition.Reason = capmeta.FailedReason
+		}
+
+		latest.Status.Conditions.UpdateConditionByType(readyCondition)
+		latest.Status.Normalize()
+
+		return r.Client.Status().Update(ctx, latest)
+	})
+}
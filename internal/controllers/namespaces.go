@@ -0,0 +1,96 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NamespaceFilter restricts the namespaces SopsSecrets, NamespacedSopsProviders
+// and key Secrets are reconciled in. The zero value selects all namespaces.
+type NamespaceFilter struct {
+	// Names of the selected namespaces, all namespaces when empty
+	Names []string
+	// Selector for the labels of the selected namespaces
+	Selector labels.Selector
+}
+
+// Matches returns true if the namespace is selected by the filter. Namespaces
+// are read with the given reader, usually the cache.
+func (f NamespaceFilter) Matches(ctx context.Context, c client.Reader, namespace string) (bool, error) {
+	if len(f.Names) > 0 && !slices.Contains(f.Names, namespace) {
+		return false, nil
+	}
+
+	if !f.selectsLabels() {
+		return true, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return f.Selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// Filter the Secrets in namespaces selected by the filter.
+func (f NamespaceFilter) filterSecrets(ctx context.Context, c client.Reader, secrets []*corev1.Secret) ([]*corev1.Secret, error) {
+	if len(f.Names) == 0 && !f.selectsLabels() {
+		return secrets, nil
+	}
+
+	selected := make([]*corev1.Secret, 0, len(secrets))
+
+	for _, secret := range secrets {
+		ok, err := f.Matches(ctx, c, secret.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			selected = append(selected, secret)
+		}
+	}
+
+	return selected, nil
+}
+
+// Whether namespaces are selected by their labels. Namespaces must be watched
+// then, as relabeling a namespace changes the selection.
+func (f NamespaceFilter) selectsLabels() bool {
+	return f.Selector != nil && !f.Selector.Empty()
+}
+
+// Watch namespaces selected by labels and enqueue the objects listed by list
+// for relabeled namespaces.
+func (f NamespaceFilter) watch(
+	bld *builder.Builder,
+	list func(ctx context.Context, namespace string) []reconcile.Request,
+) *builder.Builder {
+	if !f.selectsLabels() {
+		return bld
+	}
+
+	return bld.Watches(
+		&corev1.Namespace{},
+		handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return list(ctx, obj.GetName())
+		}),
+		builder.WithPredicates(namespaceLabelsChangedPredicate()),
+	)
+}
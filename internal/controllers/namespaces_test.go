@@ -0,0 +1,69 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNamespaceFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "solar-prod", Labels: map[string]string{"group": "solar"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "wind-prod", Labels: map[string]string{"group": "wind"}}},
+	).Build()
+
+	tests := map[string]struct {
+		filter NamespaceFilter
+		want   map[string]bool
+	}{
+		"all namespaces": {
+			want: map[string]bool{"solar-prod": true, "wind-prod": true, "missing": true},
+		},
+		"names": {
+			filter: NamespaceFilter{Names: []string{"solar-prod"}},
+			want:   map[string]bool{"solar-prod": true, "wind-prod": false},
+		},
+		"selector": {
+			filter: NamespaceFilter{Selector: labels.SelectorFromSet(labels.Set{"group": "wind"})},
+			want:   map[string]bool{"solar-prod": false, "wind-prod": true, "missing": false},
+		},
+		"names and selector": {
+			filter: NamespaceFilter{Names: []string{"solar-prod"}, Selector: labels.SelectorFromSet(labels.Set{"group": "wind"})},
+			want:   map[string]bool{"solar-prod": false, "wind-prod": false},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for namespace, want := range tt.want {
+				got, err := tt.filter.Matches(ctx, c, namespace)
+				require.NoError(t, err)
+				require.Equal(t, want, got, namespace)
+			}
+		})
+	}
+
+	secrets := []*corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "solar-prod"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "wind-prod"}},
+	}
+
+	selected, err := NamespaceFilter{Names: []string{"wind-prod"}}.filterSecrets(ctx, c, secrets)
+	require.NoError(t, err)
+	require.Equal(t, secrets[1:], selected)
+}
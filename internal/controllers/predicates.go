@@ -10,6 +10,7 @@ import (
 	"github.com/peak-scale/sops-operator/internal/api"
 	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -24,6 +25,17 @@ func primaryResourcePredicate() predicate.Predicate {
 	)
 }
 
+// namespaceLabelsChangedPredicate only passes label changes of namespaces,
+// which may change the namespaces selected by a NamespaceFilter.
+func namespaceLabelsChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		UpdateFunc:  predicate.LabelChangedPredicate{}.Update,
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
 // sopsProviderStatusPredicate only fans provider updates out to secret
 // controllers when the usable provider set or readiness changes. In
 // particular, condition timestamps, messages, reasons and observed generation
@@ -32,20 +44,32 @@ func sopsProviderStatusPredicate() predicate.Predicate {
 	return predicate.Funcs{
 		CreateFunc: func(event.CreateEvent) bool { return true },
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			oldProvider, oldOK := e.ObjectOld.(*sopsv1alpha1.SopsProvider)
+			oldStatus, oldOK := providerStatus(e.ObjectOld)
 
-			newProvider, newOK := e.ObjectNew.(*sopsv1alpha1.SopsProvider)
+			newStatus, newOK := providerStatus(e.ObjectNew)
 			if !oldOK || !newOK {
 				return false
 			}
 
-			return providerStatusChanged(&oldProvider.Status, &newProvider.Status)
+			return providerStatusChanged(oldStatus, newStatus)
 		},
 		DeleteFunc:  func(event.DeleteEvent) bool { return true },
 		GenericFunc: func(event.GenericEvent) bool { return false },
 	}
 }
 
+// Status of a SopsProvider or NamespacedSopsProvider.
+func providerStatus(obj client.Object) (*sopsv1alpha1.SopsProviderStatus, bool) {
+	switch provider := obj.(type) {
+	case *sopsv1alpha1.SopsProvider:
+		return &provider.Status, true
+	case *sopsv1alpha1.NamespacedSopsProvider:
+		return &provider.Status, true
+	default:
+		return nil, false
+	}
+}
+
 type providerReadiness struct {
 	status  metav1.ConditionStatus
 	present bool
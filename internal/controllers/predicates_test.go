@@ -12,6 +12,7 @@ import (
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
 	"github.com/peak-scale/sops-operator/internal/api"
 	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -106,6 +107,19 @@ func TestSopsProviderStatusPredicateLifecycleEvents(t *testing.T) {
 	require.False(t, predicate.Generic(event.GenericEvent{Object: p}))
 }
 
+func TestSopsProviderStatusPredicateNamespaced(t *testing.T) {
+	t.Parallel()
+
+	oldProvider := &sopsv1alpha1.NamespacedSopsProvider{}
+	newProvider := oldProvider.DeepCopy()
+	newProvider.Status.Providers = append(newProvider.Status.Providers,
+		&sopsv1alpha1.SopsProviderItemStatus{Origin: api.Origin{UID: types.UID("uid-1")}})
+
+	predicate := sopsProviderStatusPredicate()
+	require.False(t, predicate.Update(event.UpdateEvent{ObjectOld: oldProvider, ObjectNew: oldProvider.DeepCopy()}))
+	require.True(t, predicate.Update(event.UpdateEvent{ObjectOld: oldProvider, ObjectNew: newProvider}))
+}
+
 func TestPrimaryResourcePredicate(t *testing.T) {
 	t.Parallel()
 
@@ -142,6 +156,27 @@ func TestPrimaryResourcePredicate(t *testing.T) {
 	})
 }
 
+func TestNamespaceLabelsChangedPredicate(t *testing.T) {
+	t.Parallel()
+
+	oldNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "solar-prod",
+		Labels: map[string]string{"group": "solar"},
+	}}
+
+	relabeled := oldNamespace.DeepCopy()
+	relabeled.Labels["group"] = "wind"
+
+	annotated := oldNamespace.DeepCopy()
+	annotated.Annotations = map[string]string{"owner": "solar"}
+
+	predicate := namespaceLabelsChangedPredicate()
+	require.True(t, predicate.Update(event.UpdateEvent{ObjectOld: oldNamespace, ObjectNew: relabeled}))
+	require.False(t, predicate.Update(event.UpdateEvent{ObjectOld: oldNamespace, ObjectNew: annotated}))
+	require.False(t, predicate.Create(event.CreateEvent{Object: oldNamespace}))
+	require.False(t, predicate.Delete(event.DeleteEvent{Object: oldNamespace}))
+}
+
 func providerWithStatus(
 	item *sopsv1alpha1.SopsProviderItemStatus,
 	ready metav1.ConditionStatus,
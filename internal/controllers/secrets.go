@@ -4,83 +4,146 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
+	"strings"
 
 	"github.com/go-logr/logr"
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
 	"github.com/peak-scale/sops-operator/internal/api"
-	"github.com/peak-scale/sops-operator/internal/api/errors"
+	errs "github.com/peak-scale/sops-operator/internal/api/errors"
+	"github.com/peak-scale/sops-operator/internal/audit"
 	"github.com/peak-scale/sops-operator/internal/decryptor"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	"github.com/peak-scale/sops-operator/internal/metrics"
+	"github.com/peak-scale/sops-operator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/csaupgrade"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-// Retrieve a Decryption Provider.
+// Retrieve the Decryption Providers, SopsProviders and for SopsSecrets also
+// NamespacedSopsProviders. The names of the matching providers are also
+// returned when the secret can not be decrypted.
 func fetchDecryptionProviders(
 	ctx context.Context,
 	c client.Client,
+	reader client.Reader,
 	log logr.Logger,
 	cfg SopsSecretReconcilerConfig,
+	recorder *metrics.Recorder,
 	status *sopsv1alpha1.SopsSecretStatus,
 	secret client.Object,
-) (sopsFile api.SopsImplementation, sops *decryptor.SOPSDecryptor, cleanup func(), err error) {
+) (
+	sopsFile api.SopsImplementation,
+	sops *decryptor.SOPSDecryptor,
+	providers []string,
+	cleanup func(),
+	err error,
+) {
+	ctx, span := tracing.Start(ctx, "fetchDecryptionProviders")
+	defer func() { tracing.End(span, err) }()
+
 	// Reset previous providers
 	status.Providers = make([]*api.Origin, 0)
 
 	// Gather all Providers
 	providerList := &sopsv1alpha1.SopsProviderList{}
-	if err := c.List(ctx, providerList); err != nil {
-		log.Error(err, "Failed to list providers")
+	if !cfg.DisableClusterProviders {
+		if err := c.List(ctx, providerList); err != nil {
+			log.Error(err, "Failed to list providers")
 
-		return nil, nil, nil, err
+			return nil, nil, nil, nil, err
+		}
 	}
 
 	// Evaluate the Providers, which are matching
-	matchingProviders := []sopsv1alpha1.SopsProvider{}
+	matchingProviders := []matchingProvider{}
+
+	for i := range providerList.Items {
+		provider := &providerList.Items[i]
+		if provider.SelectsSecret(ctx, c, secret) {
+			matchingProviders = append(matchingProviders, matchingProvider{
+				name:   provider.Name,
+				origin: api.NewOrigin(provider),
+				status: &provider.Status,
+				refs:   provider.Spec.KeySecretRefs,
+			})
+		}
+	}
 
-	for _, provider := range providerList.Items {
-		// match state for provider
-		providerMatch := false
+	total := len(providerList.Items)
 
-		for _, selector := range provider.Spec.SOPSSelectors {
-			match, err := selector.SingleMatch(ctx, c, secret)
-			if err != nil {
-				continue
-			}
+	// Namespaced Providers only serve SopsSecrets
+	if secret.GetNamespace() != "" {
+		namespacedList := &sopsv1alpha1.NamespacedSopsProviderList{}
+		if err := c.List(ctx, namespacedList); err != nil {
+			log.Error(err, "Failed to list namespaced providers")
 
-			if match {
-				providerMatch = true
-
-				break
-			}
+			return nil, nil, nil, nil, err
 		}
 
-		// Append Provider if matched
-		if providerMatch {
-			matchingProviders = append(matchingProviders, provider)
+		total += len(namespacedList.Items)
+
+		for i := range namespacedList.Items {
+			provider := &namespacedList.Items[i]
+			if provider.SelectsSecret(ctx, c, secret) {
+				matchingProviders = append(matchingProviders, matchingProvider{
+					name:   provider.ProviderName(),
+					origin: api.NewOrigin(provider),
+					status: &provider.Status,
+				})
+			}
 		}
 	}
 
 	log.V(5).Info("evaluated providers", "matching", len(matchingProviders))
 
+	span.SetAttributes(
+		attribute.Int("providers.total", total),
+		attribute.Int("providers.matching", len(matchingProviders)),
+	)
+
+	servingProviders := make([]string, 0, len(matchingProviders))
+	for _, provider := range matchingProviders {
+		servingProviders = append(servingProviders, provider.name)
+	}
+
+	recorder.RecordServedSecret(secret, servingProviders)
+
 	// No providers throws an error
 	if len(matchingProviders) == 0 {
-		return nil, nil, nil, errors.NewNoDecryptionProviderError(secret)
+		recorder.RecordDecryptionFailure(metrics.DecryptionFailureNoProvider)
+
+		return nil, nil, nil, nil, errs.NewNoDecryptionProviderError(secret)
 	}
 
 	// Initialize Temporary Decryptor
 	decryptor, cleanup, err := decryptor.NewSOPSTempDecryptor()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, servingProviders, nil, err
 	}
 
+	decryptor.SetObserver(recorder)
+
 	if !cfg.EnableStatus && len(status.Providers) > 0 {
 		status.Providers = []*api.Origin{}
 	}
@@ -88,16 +151,14 @@ func fetchDecryptionProviders(
 	// Gather Secrets from Provider
 	for _, provider := range matchingProviders {
 		if cfg.EnableStatus {
-			status.Providers = append(status.Providers,
-				api.NewOrigin(&provider),
-			)
+			status.Providers = append(status.Providers, provider.origin)
 		}
 
-		for _, sec := range provider.Status.Providers {
+		for _, sec := range provider.status.Providers {
 			if sec.Status == metav1.ConditionTrue {
 				log.V(7).Info("adding secret from provider", "secret", sec.Name)
 
-				if err := decryptor.KeysFromSecret(ctx, c, sec.Name, sec.Namespace); err != nil {
+				if err := loadProviderKeys(ctx, c, reader, decryptor, provider.refs, sec.Name, sec.Namespace); err != nil {
 					log.Error(err, "provider secret error")
 				}
 			}
@@ -106,17 +167,73 @@ func fetchDecryptionProviders(
 
 	sopsFormat, encrypted, err := sops.IsEncrypted(secret)
 	if err != nil {
-		return nil, nil, nil, err
+		cleanup()
+
+		return nil, nil, servingProviders, nil, err
 	}
 
 	// Reject unencrypted secrets
 	if !encrypted {
+		cleanup()
+		recorder.RecordDecryptionFailure(metrics.DecryptionFailureNotEncrypted)
+
 		err = fmt.Errorf("secret missing SOPS encryption marker (not encrypted)")
 
-		return nil, nil, nil, err
+		return nil, nil, servingProviders, nil, err
 	}
 
-	return sopsFormat, decryptor, cleanup, nil
+	return sopsFormat, decryptor, servingProviders, cleanup, nil
+}
+
+// A SopsProvider or NamespacedSopsProvider selecting a secret.
+type matchingProvider struct {
+	// Name of the provider, qualified by the namespace for NamespacedSopsProviders
+	name   string
+	origin *api.Origin
+	status *sopsv1alpha1.SopsProviderStatus
+	// Key Secrets referenced explicitly by the provider
+	refs []*api.KeySecretReference
+}
+
+// Load the keys of a key Secret of a provider. Referenced key Secrets are read
+// with the reader, only their referenced keys are loaded.
+func loadProviderKeys(
+	ctx context.Context,
+	c client.Client,
+	reader client.Reader,
+	sops *decryptor.SOPSDecryptor,
+	refs []*api.KeySecretReference,
+	name string,
+	namespace string,
+) error {
+	for _, ref := range refs {
+		if !ref.Matches(namespace, name) {
+			continue
+		}
+
+		secret, err := ref.Fetch(ctx, reader)
+		if err != nil {
+			return err
+		}
+
+		return sops.KeysFromData(secret.Data, fmt.Sprintf("decryption Secret '%s'", name))
+	}
+
+	return sops.KeysFromSecret(ctx, c, name, namespace)
+}
+
+// Outcome of reconciling a single Secret Item.
+type secretOutcome struct {
+	// Deviations of the live secret from its desired state
+	Drift []string
+	// Fields managed by others when merging
+	Conflicts []string
+	// Operation on the secret, empty if it was not written
+	Operation string
+	// Checksum of the desired state of the secret
+	Checksum string
+	// Recipients which decrypted the secret
+	Recipients []decryptor.Recipient
 }
 
 // Reconcile a single Secret Item.
@@ -124,12 +241,15 @@ func reconcileSecret(
 	ctx context.Context,
 	c client.Client,
 	log logr.Logger,
+	auditor *audit.ObjectLogger,
 	origin api.SopsImplementation,
-	decryptor *decryptor.SOPSDecryptor,
+	sopsDecryptor *decryptor.SOPSDecryptor,
 	item *sopsv1alpha1.SopsSecretItem,
 	itemNamespace string,
 	metadata sopsv1alpha1.SecretMetadata,
-) (target *corev1.Secret, err error) {
+	policy sopsv1alpha1.DriftPolicy,
+	labelGenerated bool,
+) (target *corev1.Secret, outcome secretOutcome, err error) {
 	// Target for Replication
 	target = &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -138,84 +258,696 @@ func reconcileSecret(
 		},
 	}
 
+	merge := item.Mode.OrDefault() == sopsv1alpha1.SecretModeMerge
+	exists := false
+
 	err = c.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: target.Namespace}, target)
 	if err == nil {
-		if y, _ := controllerutil.HasOwnerReference(target.OwnerReferences, origin, c.Scheme()); !y {
+		if y, _ := controllerutil.HasOwnerReference(target.OwnerReferences, origin, c.Scheme()); !y && !merge {
 			err = fmt.Errorf("secret %s/%s already present, but not provisioned by sops-controller", target.Name, target.Namespace)
 
-			return target, err
+			return target, outcome, err
+		}
+
+		exists = true
+	}
+
+	if merge && !exists {
+		return target, outcome, fmt.Errorf("secret %s/%s must be present to merge into", target.Name, target.Namespace)
+	}
+
+	ctx, recipients := decryptor.WithRecipients(ctx)
+
+	if err := sopsDecryptor.Decrypt(ctx, origin.GetSopsMetadata(), item, log); err != nil {
+		auditor.Decryption(ctx, item.Name, recipients.List(), err)
+
+		return target, outcome, fmt.Errorf("secret could not be decrypted")
+	}
+
+	data, err := sourceData(ctx, c, log, sopsDecryptor, item, itemNamespace)
+
+	outcome.Recipients = recipients.List()
+	auditor.Decryption(ctx, item.Name, outcome.Recipients, err)
+
+	if err != nil {
+		return target, outcome, err
+	}
+
+	inline, err := secretData(item)
+	if err != nil {
+		return target, outcome, err
+	}
+
+	maps.Copy(data, inline)
+
+	labels := make(map[string]string)
+	maps.Copy(labels, metadata.Labels)
+	maps.Copy(labels, item.Labels)
+
+	annotations := make(map[string]string)
+	maps.Copy(annotations, metadata.Annotations)
+	maps.Copy(annotations, item.Annotations)
+
+	outcome.Checksum, err = secretChecksum(data, labels, annotations, item.Type)
+	if err != nil {
+		return target, outcome, err
+	}
+
+	if merge {
+		resourceVersion := target.ResourceVersion
+
+		outcome.Conflicts, err = mergeSecret(ctx, c, origin, target, data)
+		if err != nil {
+			return target, outcome, err
+		}
+
+		if target.ResourceVersion != resourceVersion {
+			outcome.Operation = metrics.SecretOperationUpdate
+		}
+
+		log.V(7).Info("merged secret", "conflicts", outcome.Conflicts)
+
+		return target, outcome, nil
+	}
+
+	policy = policy.OrDefault()
+
+	// Drift is only evaluated when the desired state did not change since the
+	// last write. Otherwise the secret is updated to the new desired state.
+	if exists && target.GetAnnotations()[meta.ChecksumAnnotation] == outcome.Checksum {
+		outcome.Drift = detectDrift(target, data, labels, annotations, item.Type, policy)
+
+		if len(outcome.Drift) == 0 || policy == sopsv1alpha1.DriftPolicyReport {
+			if len(outcome.Drift) > 0 {
+				log.V(5).Info("drift detected, not repairing", "drift", outcome.Drift)
+			}
+
+			// Without the label the secret is not cached in the restricted
+			// secret cache mode, so it is added independent of the drift
+			if labelGenerated && target.Labels[meta.GeneratedSecretLabel] != "true" {
+				if err := labelGeneratedSecret(ctx, c, target); err != nil {
+					return target, outcome, err
+				}
+
+				outcome.Operation = metrics.SecretOperationUpdate
+			}
+
+			return target, outcome, nil
 		}
+
+		log.V(5).Info("drift detected, repairing", "drift", outcome.Drift)
 	}
 
-	if err := decryptor.Decrypt(origin.GetSopsMetadata(), item, log); err != nil {
-		return target, fmt.Errorf("secret could not be decrypted")
+	// Selects the secret for the restricted secret cache mode. The label is
+	// neither part of the checksum nor of the drift, so enabling the mode does
+	// not rewrite all secrets.
+	if labelGenerated {
+		labels[meta.GeneratedSecretLabel] = "true"
 	}
 
 	// Replicate Secret
-	_, cerr := controllerutil.CreateOrUpdate(ctx, c, target, func() error {
-		labels := target.GetLabels()
-		if labels == nil {
-			labels = map[string]string{}
+	if err := applySecret(ctx, c, origin, target, exists, data, labels, annotations, item.Type, outcome.Checksum, policy); err != nil {
+		return target, outcome, err
+	}
+
+	outcome.Operation = metrics.SecretOperationCreate
+	if exists {
+		outcome.Operation = metrics.SecretOperationUpdate
+	}
+
+	log.V(7).Info("applied secret", "manifest", "secret")
+
+	return target, outcome, nil
+}
+
+// Write a generated Secret using server-side apply. Labels, annotations and keys
+// which are no longer part of the desired state are pruned, while fields owned
+// by other field managers are kept. Keys added by others are only kept when
+// explicitly allowed by the drift policy.
+func applySecret(
+	ctx context.Context,
+	c client.Client,
+	origin api.SopsImplementation,
+	target *corev1.Secret,
+	exists bool,
+	data map[string][]byte,
+	labels map[string]string,
+	annotations map[string]string,
+	secretType corev1.SecretType,
+	checksum string,
+	policy sopsv1alpha1.DriftPolicy,
+) error {
+	if exists {
+		if err := upgradeManagedFields(ctx, c, target); err != nil {
+			return err
+		}
+
+		if policy != sopsv1alpha1.DriftPolicyAllowExtraKeys {
+			if err := removeExtraKeys(ctx, c, target, data); err != nil {
+				return err
+			}
 		}
+	}
+
+	gvk, err := apiutil.GVKForObject(origin, c.Scheme())
+	if err != nil {
+		return err
+	}
+
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+
+	applied := make(map[string]string, len(annotations)+1)
+	maps.Copy(applied, annotations)
+	applied[meta.ChecksumAnnotation] = checksum
+
+	secret := corev1ac.Secret(target.Name, target.Namespace).
+		WithLabels(labels).
+		WithAnnotations(applied).
+		WithData(data).
+		WithType(secretType).
+		WithOwnerReferences(metav1ac.OwnerReference().
+			WithAPIVersion(gvk.GroupVersion().String()).
+			WithKind(gvk.Kind).
+			WithName(origin.GetName()).
+			WithUID(origin.GetUID()),
+		)
+
+	if err := c.Apply(ctx, secret, client.FieldOwner(meta.FieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	// Reflect the applied state on the target
+	content, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
 
-		maps.Copy(labels, metadata.Labels)
-		maps.Copy(labels, item.Labels)
+	result := &corev1.Secret{}
+	if err := json.Unmarshal(content, result); err != nil {
+		return err
+	}
 
-		target.SetLabels(labels)
+	*target = *result
+
+	return nil
+}
+
+// Add the label of generated secrets for the restricted secret cache mode,
+// without applying the rest of the desired state.
+func labelGeneratedSecret(ctx context.Context, c client.Client, target *corev1.Secret) error {
+	base := target.DeepCopy()
+
+	if target.Labels == nil {
+		target.Labels = make(map[string]string, 1)
+	}
 
-		annotations := target.GetAnnotations()
-		if annotations == nil {
-			annotations = map[string]string{}
+	target.Labels[meta.GeneratedSecretLabel] = "true"
+
+	return c.Patch(ctx, target, client.MergeFrom(base), client.FieldOwner(meta.FieldManager))
+}
+
+// Secrets written before server-side apply was used are owned by the update
+// field manager of the operator. Their managed fields are migrated to the
+// apply field manager, so fields no longer desired are pruned.
+func upgradeManagedFields(ctx context.Context, c client.Client, target *corev1.Secret) error {
+	legacyManager, _, _ := strings.Cut(rest.DefaultKubernetesUserAgent(), "/")
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(target, sets.New(legacyManager), meta.FieldManager)
+	if err != nil {
+		return err
+	}
+
+	if patch == nil {
+		return nil
+	}
+
+	return c.Patch(ctx, target, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// Remove keys from the live Secret which are not part of the desired state.
+func removeExtraKeys(ctx context.Context, c client.Client, target *corev1.Secret, data map[string][]byte) error {
+	base := target.DeepCopy()
+	removed := false
+
+	for k := range target.Data {
+		if _, ok := data[k]; !ok {
+			delete(target.Data, k)
+
+			removed = true
 		}
+	}
 
-		maps.Copy(annotations, metadata.Annotations)
-		maps.Copy(annotations, item.Annotations)
+	if !removed {
+		return nil
+	}
 
-		target.SetAnnotations(annotations)
+	return c.Patch(ctx, target, client.MergeFrom(base), client.FieldOwner(meta.FieldManager))
+}
 
-		target.Data = make(map[string][]byte, len(item.Data))
+// Field manager used to merge keys of the given origin. Each origin has its own
+// field manager, so multiple origins can merge into the same secret.
+func mergeFieldManager(origin client.Object) string {
+	return meta.FieldManager + "/" + string(origin.GetUID())
+}
 
-		for k, v := range item.Data {
-			decoded, err := base64.StdEncoding.DecodeString(v)
-			if err != nil {
-				return fmt.Errorf("failed to decode secret data key %s: %w", k, err)
+// Merge the decrypted keys into an existing Secret, without taking ownership
+// of it. Keys managed by others are left alone and returned as conflicts,
+// unless the owner of the secret allows taking them over.
+func mergeSecret(
+	ctx context.Context,
+	c client.Client,
+	origin api.SopsImplementation,
+	target *corev1.Secret,
+	data map[string][]byte,
+) (conflicts []string, err error) {
+	// Only the data is merged, the metadata of the secret stays with its owner
+	secret := corev1ac.Secret(target.Name, target.Namespace).
+		WithData(data)
+
+	manager := client.FieldOwner(mergeFieldManager(origin))
+
+	err = c.Apply(ctx, secret, manager)
+	if apierrors.IsConflict(err) {
+		conflicts = conflictCauses(err)
+
+		if target.Annotations[meta.MergeForceAnnotation] == "true" {
+			err = c.Apply(ctx, secret, manager, client.ForceOwnership)
+		} else {
+			merged := maps.Clone(data)
+			for _, key := range conflictingKeys(err) {
+				delete(merged, key)
 			}
 
-			target.Data[k] = decoded
+			secret = corev1ac.Secret(target.Name, target.Namespace).
+				WithData(merged)
+
+			err = c.Apply(ctx, secret, manager)
 		}
+	}
 
-		target.StringData = item.StringData
-		target.Type = item.Type
+	if err != nil {
+		return conflicts, err
+	}
 
-		log.V(7).Info("patching secret", "manifest", "secret")
+	if secret.UID != nil {
+		target.UID = *secret.UID
+	}
 
-		// We set owner reference to the secret
-		return controllerutil.SetOwnerReference(origin, target, c.Scheme())
-	})
-	if cerr != nil {
-		return target, cerr
+	if secret.ResourceVersion != nil {
+		target.ResourceVersion = *secret.ResourceVersion
 	}
 
-	return target, nil
+	return conflicts, nil
+}
+
+// Remove the keys merged by the given origin. Keys which are also managed by
+// others are kept.
+func unmergeSecret(ctx context.Context, c client.Client, origin client.Object, name, namespace string) error {
+	// Applying to an absent secret would create it
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &corev1.Secret{}); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	return c.Apply(ctx, corev1ac.Secret(name, namespace), client.FieldOwner(mergeFieldManager(origin)))
+}
+
+// Remove a secret which is no longer part of the desired state. Merged secrets
+// are not owned by the operator, therefore only the merged keys are removed.
+func removeSecret(ctx context.Context, c client.Client, origin client.Object, sec *sopsv1alpha1.SopsSecretItemStatus) error {
+	if sec.Mode == sopsv1alpha1.SecretModeMerge {
+		return unmergeSecret(ctx, c, origin, sec.Name, sec.Namespace)
+	}
+
+	return client.IgnoreNotFound(c.Delete(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sec.Name,
+			Namespace: sec.Namespace,
+		},
+	}))
+}
+
+// Operation on the generated secret when removing it.
+func removeOperation(sec *sopsv1alpha1.SopsSecretItemStatus) string {
+	if sec.Mode == sopsv1alpha1.SecretModeMerge {
+		return metrics.SecretOperationUpdate
+	}
+
+	return metrics.SecretOperationDelete
+}
+
+// Merged secrets are not garbage collected via owner references. The finalizer
+// is kept as long as the origin merges or has merged keys into secrets.
+func reconcileMergeFinalizer(
+	ctx context.Context,
+	c client.Client,
+	origin client.Object,
+	status *sopsv1alpha1.SopsSecretStatus,
+	items []*sopsv1alpha1.SopsSecretItem,
+) error {
+	required := false
+
+	for _, item := range items {
+		if item.Mode.OrDefault() == sopsv1alpha1.SecretModeMerge {
+			required = true
+		}
+	}
+
+	for _, sec := range status.Secrets {
+		if sec.Mode == sopsv1alpha1.SecretModeMerge {
+			required = true
+		}
+	}
+
+	patch := client.MergeFrom(origin.DeepCopyObject().(client.Object))
+
+	var changed bool
+	if required {
+		changed = controllerutil.AddFinalizer(origin, meta.MergeFinalizer)
+	} else {
+		changed = controllerutil.RemoveFinalizer(origin, meta.MergeFinalizer)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return c.Patch(ctx, origin, patch)
+}
+
+// Remove all merged keys of a deleted origin and release its finalizer.
+func finalizeMergedSecrets(
+	ctx context.Context,
+	c client.Client,
+	origin client.Object,
+	status *sopsv1alpha1.SopsSecretStatus,
+) error {
+	if !controllerutil.ContainsFinalizer(origin, meta.MergeFinalizer) {
+		return nil
+	}
+
+	for _, sec := range status.Secrets {
+		if sec.Mode != sopsv1alpha1.SecretModeMerge {
+			continue
+		}
+
+		if err := unmergeSecret(ctx, c, origin, sec.Name, sec.Namespace); err != nil {
+			return err
+		}
+	}
+
+	patch := client.MergeFrom(origin.DeepCopyObject().(client.Object))
+	controllerutil.RemoveFinalizer(origin, meta.MergeFinalizer)
+
+	return c.Patch(ctx, origin, patch)
+}
+
+// Extract the conflicting fields from a server-side apply conflict.
+func conflictCauses(err error) []string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return []string{err.Error()}
+	}
+
+	conflicts := make([]string, 0, len(status.Status().Details.Causes))
+	for _, cause := range status.Status().Details.Causes {
+		conflicts = append(conflicts, cause.Field+": "+cause.Message)
+	}
+
+	slices.Sort(conflicts)
+
+	return conflicts
+}
+
+// Keys of the data which caused an apply conflict.
+func conflictingKeys(err error) []string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(status.Status().Details.Causes))
+	for _, cause := range status.Status().Details.Causes {
+		if key, ok := strings.CutPrefix(cause.Field, ".data."); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Decoded data of a decrypted Secret Item. StringData takes precedence
+// over Data, the same way the API server merges them.
+func secretData(item *sopsv1alpha1.SopsSecretItem) (map[string][]byte, error) {
+	data := make(map[string][]byte, len(item.Data)+len(item.StringData))
+
+	for k, v := range item.Data {
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secret data key %s: %w", k, err)
+		}
+
+		data[k] = decoded
+	}
+
+	for k, v := range item.StringData {
+		data[k] = []byte(v)
+	}
+
+	return data, nil
+}
+
+// Decrypted data of the documents referenced or embedded by a Secret Item.
+// Later documents take precedence over earlier ones.
+func sourceData(
+	ctx context.Context,
+	c client.Client,
+	log logr.Logger,
+	sopsDecryptor *decryptor.SOPSDecryptor,
+	item *sopsv1alpha1.SopsSecretItem,
+	namespace string,
+) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+
+	for i := range item.DataFrom {
+		source := &item.DataFrom[i]
+
+		document, err := fetchDataSource(ctx, c, source, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("dataFrom[%d]: %w", i, err)
+		}
+
+		if document == nil {
+			log.V(5).Info("optional document not found", "key", source.Key())
+
+			continue
+		}
+
+		decrypted, err := sopsDecryptor.DecryptDocument(
+			ctx,
+			document,
+			decryptor.FormatFor(source.Key(), string(source.Format)),
+			source.Key(),
+			log,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("dataFrom[%d]: failed to decrypt document: %w", i, err)
+		}
+
+		maps.Copy(data, decrypted)
+	}
+
+	if item.EnvData != nil {
+		format := item.EnvData.Format
+		if format == "" {
+			format = sopsv1alpha1.DocumentFormatDotenv
+		}
+
+		decrypted, err := sopsDecryptor.DecryptDocument(
+			ctx,
+			[]byte(item.EnvData.Content),
+			decryptor.FormatFor("", string(format)),
+			"",
+			log,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("envData: failed to decrypt document: %w", err)
+		}
+
+		maps.Copy(data, decrypted)
+	}
+
+	return data, nil
+}
+
+// Retrieve the document referenced by a data source. Missing optional
+// references return no document.
+func fetchDataSource(
+	ctx context.Context,
+	c client.Client,
+	source *sopsv1alpha1.SopsDataSource,
+	namespace string,
+) ([]byte, error) {
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+
+		optional := ptr.Deref(ref.Optional, false)
+
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, cm); err != nil {
+			if optional && apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("failed to get configmap %s/%s: %w", namespace, ref.Name, err)
+		}
+
+		if value, ok := cm.Data[ref.Key]; ok {
+			return []byte(value), nil
+		}
+
+		if value, ok := cm.BinaryData[ref.Key]; ok {
+			return value, nil
+		}
+
+		if optional {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("key %q not found in configmap %s/%s", ref.Key, namespace, ref.Name)
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+
+		optional := ptr.Deref(ref.Optional, false)
+
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+			if optional && apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, ref.Name, err)
+		}
+
+		if value, ok := secret.Data[ref.Key]; ok {
+			return value, nil
+		}
+
+		if optional {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("key %q not found in secret %s/%s", ref.Key, namespace, ref.Name)
+	default:
+		return nil, fmt.Errorf("either configMapKeyRef or secretKeyRef must be set")
+	}
+}
+
+// Checksum of the desired state of a generated Secret.
+func secretChecksum(
+	data map[string][]byte,
+	labels map[string]string,
+	annotations map[string]string,
+	secretType corev1.SecretType,
+) (string, error) {
+	// Maps are marshalled with sorted keys, which keeps the checksum stable
+	content, err := json.Marshal(struct {
+		Data        map[string][]byte `json:"data"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+		Type        corev1.SecretType `json:"type"`
+	}{data, labels, annotations, secretType})
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate secret checksum: %w", err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+// Lists the deviations of a live Secret from its desired state.
+func detectDrift(
+	live *corev1.Secret,
+	data map[string][]byte,
+	labels map[string]string,
+	annotations map[string]string,
+	secretType corev1.SecretType,
+	policy sopsv1alpha1.DriftPolicy,
+) (drift []string) {
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+
+	if live.Type != secretType {
+		drift = append(drift, "type modified")
+	}
+
+	for k, v := range data {
+		current, ok := live.Data[k]
+
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("key %q removed", k))
+		case !bytes.Equal(current, v):
+			drift = append(drift, fmt.Sprintf("key %q modified", k))
+		}
+	}
+
+	if policy != sopsv1alpha1.DriftPolicyAllowExtraKeys {
+		for k := range live.Data {
+			if _, ok := data[k]; !ok {
+				drift = append(drift, fmt.Sprintf("key %q added", k))
+			}
+		}
+	}
+
+	for k, v := range labels {
+		current, ok := live.Labels[k]
+
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("label %q removed", k))
+		case current != v:
+			drift = append(drift, fmt.Sprintf("label %q modified", k))
+		}
+	}
+
+	for k, v := range annotations {
+		current, ok := live.Annotations[k]
+
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("annotation %q removed", k))
+		case current != v:
+			drift = append(drift, fmt.Sprintf("annotation %q modified", k))
+		}
+	}
+
+	slices.Sort(drift)
+
+	return drift
 }
 
 // Delete all decrypted secrets.
 func cleanupSecrets(
 	ctx context.Context,
 	c client.Client,
+	recorder *metrics.Recorder,
+	auditor *audit.ObjectLogger,
+	origin client.Object,
 	status *sopsv1alpha1.SopsSecretStatus,
 ) (err error) {
 	for _, sec := range status.Secrets {
-		err := c.Delete(ctx, &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      sec.Name,
-				Namespace: sec.Namespace,
-			},
-		})
-		if err != nil && !apierrors.IsNotFound(err) {
+		if err := removeSecret(ctx, c, origin, sec); err != nil {
+			auditor.SecretRemoved(ctx, removeOperation(sec), sec.Namespace, sec.Name, err)
+
 			return err
 		}
 
+		recorder.RecordSecretOperation(removeOperation(sec))
+		auditor.SecretRemoved(ctx, removeOperation(sec), sec.Namespace, sec.Name, nil)
+
 		status.RemoveInstance(&sopsv1alpha1.SopsSecretItemStatus{
 			Name:      sec.Name,
 			Namespace: sec.Namespace,
@@ -0,0 +1,605 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"filippo.io/age"
+	"github.com/go-logr/logr"
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	"github.com/peak-scale/sops-operator/internal/kubectl"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	"github.com/peak-scale/sops-operator/internal/metrics"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestDetectDrift(t *testing.T) {
+	t.Parallel()
+
+	desired := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("secret"),
+	}
+
+	tests := map[string]struct {
+		mutate func(*corev1.Secret)
+		policy sopsv1alpha1.DriftPolicy
+		want   []string
+	}{
+		"in sync": {
+			policy: sopsv1alpha1.DriftPolicyRepair,
+		},
+		"modified key": {
+			mutate: func(secret *corev1.Secret) {
+				secret.Data["password"] = []byte("changed")
+			},
+			policy: sopsv1alpha1.DriftPolicyRepair,
+			want:   []string{`key "password" modified`},
+		},
+		"removed key": {
+			mutate: func(secret *corev1.Secret) {
+				delete(secret.Data, "username")
+			},
+			policy: sopsv1alpha1.DriftPolicyReport,
+			want:   []string{`key "username" removed`},
+		},
+		"extra key": {
+			mutate: func(secret *corev1.Secret) {
+				secret.Data["extra"] = []byte("value")
+			},
+			policy: sopsv1alpha1.DriftPolicyRepair,
+			want:   []string{`key "extra" added`},
+		},
+		"extra key allowed": {
+			mutate: func(secret *corev1.Secret) {
+				secret.Data["extra"] = []byte("value")
+			},
+			policy: sopsv1alpha1.DriftPolicyAllowExtraKeys,
+		},
+		"label and annotation modified": {
+			mutate: func(secret *corev1.Secret) {
+				secret.Labels["app"] = "other"
+				secret.Annotations["team"] = "lunar"
+			},
+			policy: sopsv1alpha1.DriftPolicyRepair,
+			want:   []string{`annotation "team" modified`, `label "app" modified`},
+		},
+		"label and annotation removed": {
+			mutate: func(secret *corev1.Secret) {
+				delete(secret.Labels, "app")
+				delete(secret.Annotations, "team")
+			},
+			policy: sopsv1alpha1.DriftPolicyRepair,
+			want:   []string{`annotation "team" removed`, `label "app" removed`},
+		},
+		"type modified": {
+			mutate: func(secret *corev1.Secret) {
+				secret.Type = corev1.SecretTypeDockerConfigJson
+			},
+			policy: sopsv1alpha1.DriftPolicyReport,
+			want:   []string{"type modified"},
+		},
+		"foreign labels are ignored": {
+			mutate: func(secret *corev1.Secret) {
+				secret.Labels["foreign"] = "value"
+			},
+			policy: sopsv1alpha1.DriftPolicyRepair,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			live := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "demo"},
+					Annotations: map[string]string{"team": "solar"},
+				},
+				Data: map[string][]byte{
+					"username": []byte("admin"),
+					"password": []byte("secret"),
+				},
+				Type: corev1.SecretTypeOpaque,
+			}
+
+			if tt.mutate != nil {
+				tt.mutate(live)
+			}
+
+			drift := detectDrift(
+				live,
+				desired,
+				map[string]string{"app": "demo"},
+				map[string]string{"team": "solar"},
+				"",
+				tt.policy,
+			)
+			require.Equal(t, tt.want, drift)
+		})
+	}
+}
+
+func TestSecretChecksum(t *testing.T) {
+	t.Parallel()
+
+	data := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	labels := map[string]string{"app": "demo"}
+
+	checksum, err := secretChecksum(data, labels, nil, corev1.SecretTypeOpaque)
+	require.NoError(t, err)
+
+	again, err := secretChecksum(data, labels, nil, corev1.SecretTypeOpaque)
+	require.NoError(t, err)
+	require.Equal(t, checksum, again)
+
+	changed, err := secretChecksum(map[string][]byte{"a": []byte("1"), "b": []byte("3")}, labels, nil, corev1.SecretTypeOpaque)
+	require.NoError(t, err)
+	require.NotEqual(t, checksum, changed)
+
+	relabeled, err := secretChecksum(data, map[string]string{"app": "other"}, nil, corev1.SecretTypeOpaque)
+	require.NoError(t, err)
+	require.NotEqual(t, checksum, relabeled)
+}
+
+func TestSecretDataPrefersStringData(t *testing.T) {
+	t.Parallel()
+
+	data, err := secretData(&sopsv1alpha1.SopsSecretItem{
+		Data:       map[string]string{"key": "ZnJvbS1kYXRh", "other": "b3RoZXI="},
+		StringData: map[string]string{"key": "from-string-data"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"key":   []byte("from-string-data"),
+		"other": []byte("other"),
+	}, data)
+
+	_, err = secretData(&sopsv1alpha1.SopsSecretItem{
+		Data: map[string]string{"key": "not base64!"},
+	})
+	require.Error(t, err)
+}
+
+func TestApplySecretPrunesRemovedMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, sopsv1alpha1.AddToScheme(scheme))
+
+	origin := &sopsv1alpha1.SopsSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "origin", Namespace: "default", UID: "origin-uid"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	target := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "generated", Namespace: "default"}}
+	data := map[string][]byte{"key": []byte("value")}
+
+	require.NoError(t, applySecret(ctx, c, origin, target, false, data,
+		map[string]string{"keep": "true", "remove": "true"},
+		map[string]string{"remove": "true"},
+		"", "checksum-1", sopsv1alpha1.DriftPolicyRepair,
+	))
+	require.Equal(t, types.UID("origin-uid"), target.OwnerReferences[0].UID)
+
+	// Another controller adds an annotation and a key to the secret
+	require.NoError(t, c.Apply(ctx, corev1ac.Secret("generated", "default").
+		WithAnnotations(map[string]string{"cert-manager.io/issuer": "issuer"}).
+		WithData(map[string][]byte{"foreign": []byte("value")}),
+		client.FieldOwner("cert-manager"),
+	))
+
+	live := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "generated", Namespace: "default"}, live))
+
+	require.NoError(t, applySecret(ctx, c, origin, live, true, data,
+		map[string]string{"keep": "true"},
+		nil,
+		"", "checksum-2", sopsv1alpha1.DriftPolicyAllowExtraKeys,
+	))
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "generated", Namespace: "default"}, live))
+	require.Equal(t, map[string]string{"keep": "true"}, live.Labels)
+	require.Equal(t, map[string]string{
+		"cert-manager.io/issuer": "issuer",
+		meta.ChecksumAnnotation:  "checksum-2",
+	}, live.Annotations)
+	require.Equal(t, map[string][]byte{
+		"key":     []byte("value"),
+		"foreign": []byte("value"),
+	}, live.Data)
+	require.Equal(t, corev1.SecretTypeOpaque, live.Type)
+
+	// Repairing removes keys added by others
+	require.NoError(t, applySecret(ctx, c, origin, live, true, data,
+		map[string]string{"keep": "true"},
+		nil,
+		"", "checksum-2", sopsv1alpha1.DriftPolicyRepair,
+	))
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "generated", Namespace: "default"}, live))
+	require.Equal(t, map[string][]byte{"key": []byte("value")}, live.Data)
+	require.Equal(t, "issuer", live.Annotations["cert-manager.io/issuer"])
+}
+
+func TestMergeSecret(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, sopsv1alpha1.AddToScheme(scheme))
+
+	origin := &sopsv1alpha1.SopsSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "origin", Namespace: "default", UID: "origin-uid"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	// Secret owned by another tool
+	require.NoError(t, c.Apply(ctx, corev1ac.Secret("existing", "default").
+		WithLabels(map[string]string{"app": "chart"}).
+		WithData(map[string][]byte{"chart": []byte("value"), "password": []byte("chart")}),
+		client.FieldOwner("helm"),
+	))
+
+	target := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"}}
+
+	conflicts, err := mergeSecret(ctx, c, origin, target,
+		map[string][]byte{"password": []byte("sops"), "token": []byte("sops")},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{`.data.password: conflict with "helm"`}, conflicts)
+
+	// Keys managed by others are left alone
+	live := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "existing", Namespace: "default"}, live))
+	require.Empty(t, live.OwnerReferences)
+	require.Equal(t, map[string]string{"app": "chart"}, live.Labels)
+	require.Equal(t, map[string][]byte{
+		"chart":    []byte("value"),
+		"password": []byte("chart"),
+		"token":    []byte("sops"),
+	}, live.Data)
+
+	// Unless the owner of the secret allows taking them over
+	require.NoError(t, c.Apply(ctx, corev1ac.Secret("existing", "default").
+		WithLabels(map[string]string{"app": "chart"}).
+		WithAnnotations(map[string]string{meta.MergeForceAnnotation: "true"}).
+		WithData(map[string][]byte{"chart": []byte("value"), "password": []byte("chart")}),
+		client.FieldOwner("helm"),
+	))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "existing", Namespace: "default"}, target))
+
+	conflicts, err = mergeSecret(ctx, c, origin, target,
+		map[string][]byte{"password": []byte("sops"), "token": []byte("sops")},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{`.data.password: conflict with "helm"`}, conflicts)
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "existing", Namespace: "default"}, live))
+	require.Equal(t, map[string][]byte{
+		"chart":    []byte("value"),
+		"password": []byte("sops"),
+		"token":    []byte("sops"),
+	}, live.Data)
+
+	// Removing the origin only removes the merged keys
+	require.NoError(t, removeSecret(ctx, c, origin, &sopsv1alpha1.SopsSecretItemStatus{
+		Name:      "existing",
+		Namespace: "default",
+		Mode:      sopsv1alpha1.SecretModeMerge,
+	}))
+
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "existing", Namespace: "default"}, live))
+	require.Equal(t, map[string]string{"app": "chart"}, live.Labels)
+	require.Equal(t, map[string][]byte{"chart": []byte("value")}, live.Data)
+
+	// Unmerging an absent secret does not create it
+	require.NoError(t, unmergeSecret(ctx, c, origin, "absent", "default"))
+	require.Error(t, c.Get(ctx, types.NamespacedName{Name: "absent", Namespace: "default"}, live))
+}
+
+func TestReconcileMergeFinalizer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, sopsv1alpha1.AddToScheme(scheme))
+
+	origin := &sopsv1alpha1.SopsSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "origin", Namespace: "default"},
+		Spec: sopsv1alpha1.SopsSecretSpec{
+			Secrets: []*sopsv1alpha1.SopsSecretItem{{Name: "existing", Mode: sopsv1alpha1.SecretModeMerge}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(origin).Build()
+
+	require.NoError(t, reconcileMergeFinalizer(ctx, c, origin, &origin.Status, origin.Spec.Secrets))
+	require.Contains(t, origin.Finalizers, meta.MergeFinalizer)
+
+	// Keys merged previously still require the finalizer
+	origin.Spec.Secrets = nil
+	origin.Status.Secrets = []*sopsv1alpha1.SopsSecretItemStatus{
+		{Name: "existing", Namespace: "default", Mode: sopsv1alpha1.SecretModeMerge},
+	}
+
+	require.NoError(t, reconcileMergeFinalizer(ctx, c, origin, &origin.Status, origin.Spec.Secrets))
+	require.Contains(t, origin.Finalizers, meta.MergeFinalizer)
+
+	origin.Status.Secrets = nil
+
+	require.NoError(t, reconcileMergeFinalizer(ctx, c, origin, &origin.Status, origin.Spec.Secrets))
+	require.NotContains(t, origin.Finalizers, meta.MergeFinalizer)
+}
+
+func TestFetchDataSource(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "documents", Namespace: "default"},
+			Data:       map[string]string{"app.env": "text"},
+			BinaryData: map[string][]byte{"payload": []byte("binary")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "documents", Namespace: "default"},
+			Data:       map[string][]byte{"app.yaml": []byte("secret")},
+		},
+	).Build()
+
+	configMapRef := func(name, key string, optional bool) sopsv1alpha1.SopsDataSource {
+		return sopsv1alpha1.SopsDataSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+			Optional:             &optional,
+		}}
+	}
+
+	tests := map[string]struct {
+		source  sopsv1alpha1.SopsDataSource
+		want    []byte
+		wantErr bool
+	}{
+		"configmap data": {
+			source: configMapRef("documents", "app.env", false),
+			want:   []byte("text"),
+		},
+		"configmap binary data": {
+			source: configMapRef("documents", "payload", false),
+			want:   []byte("binary"),
+		},
+		"secret data": {
+			source: sopsv1alpha1.SopsDataSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "documents"},
+				Key:                  "app.yaml",
+			}},
+			want: []byte("secret"),
+		},
+		"missing key": {
+			source:  configMapRef("documents", "absent", false),
+			wantErr: true,
+		},
+		"missing optional key": {
+			source: configMapRef("documents", "absent", true),
+		},
+		"missing configmap": {
+			source:  configMapRef("absent", "app.env", false),
+			wantErr: true,
+		},
+		"missing optional configmap": {
+			source: configMapRef("absent", "app.env", true),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			document, err := fetchDataSource(context.Background(), c, &tt.source, "default")
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, document)
+		})
+	}
+}
+
+func TestSourceDataEnvData(t *testing.T) {
+	t.Parallel()
+
+	key, err := os.ReadFile("../decryptor/testdata/age.agekey")
+	require.NoError(t, err)
+
+	document, err := os.ReadFile("../decryptor/testdata/document.env")
+	require.NoError(t, err)
+
+	d := decryptor.NewSOPSDecryptor("")
+	require.NoError(t, d.AddAgeKey(key))
+
+	c := fake.NewClientBuilder().Build()
+
+	data, err := sourceData(context.Background(), c, logr.Discard(), d, &sopsv1alpha1.SopsSecretItem{
+		EnvData: &sopsv1alpha1.SopsEnvData{Content: string(document)},
+	}, "default")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"DATABASE_USER":     []byte("admin"),
+		"DATABASE_PASSWORD": []byte("secret"),
+	}, data)
+
+	_, err = sourceData(context.Background(), c, logr.Discard(), d, &sopsv1alpha1.SopsSecretItem{
+		EnvData: &sopsv1alpha1.SopsEnvData{Format: sopsv1alpha1.DocumentFormatINI, Content: "not encrypted"},
+	}, "default")
+	require.ErrorContains(t, err, "envData")
+}
+
+func TestFetchDecryptionProvidersNamespaced(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, sopsv1alpha1.AddToScheme(scheme))
+
+	namespace := func(name, tenant string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{capmeta.NewTenantLabel: tenant},
+		}}
+	}
+
+	provider := func(namespace, name string, tenantNamespaces bool) *sopsv1alpha1.NamespacedSopsProvider {
+		return &sopsv1alpha1.NamespacedSopsProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: sopsv1alpha1.NamespacedSopsProviderSpec{
+				SecretSelectors:  []*metav1.LabelSelector{{MatchLabels: map[string]string{"app": "x"}}},
+				TenantNamespaces: tenantNamespaces,
+			},
+		}
+	}
+
+	secret := &sopsv1alpha1.SopsSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret", Namespace: "tenant-a", Labels: map[string]string{"app": "x"}},
+		Sops:       &api.Metadata{},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		namespace("tenant-a", "acme"),
+		namespace("tenant-b", "acme"),
+		namespace("other", "globex"),
+		provider("tenant-a", "local", false),
+		provider("tenant-b", "confined", false),
+		provider("tenant-b", "shared", true),
+		provider("other", "shared", true),
+		secret,
+	).Build()
+
+	_, _, providers, cleanup, err := fetchDecryptionProviders(
+		ctx, c, c, logr.Discard(), SopsSecretReconcilerConfig{EnableStatus: true}, metrics.NewRecorder(), &secret.Status, secret,
+	)
+	require.NoError(t, err)
+
+	defer cleanup()
+
+	require.ElementsMatch(t, []string{"tenant-a/local", "tenant-b/shared"}, providers)
+	require.Len(t, secret.Status.Providers, 2)
+
+	// GlobalSopsSecrets are not served by namespaced providers
+	global := &sopsv1alpha1.GlobalSopsSecret{
+		ObjectMeta: metav1.ObjectMeta{Name: "global", Labels: map[string]string{"app": "x"}},
+		Sops:       &api.Metadata{},
+	}
+
+	_, _, _, _, err = fetchDecryptionProviders(
+		ctx, c, c, logr.Discard(), SopsSecretReconcilerConfig{}, metrics.NewRecorder(), &global.Status, global,
+	)
+	require.Error(t, err)
+}
+
+func TestReconcileSecretLabelsGeneratedSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, sopsv1alpha1.AddToScheme(scheme))
+
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".sops.yaml"), []byte(`creation_rules:
+  - path_regex: \.yaml$
+    age: `+identity.Recipient().String()+`
+`), 0o600))
+
+	encrypted, err := kubectl.Encrypt([]byte(`apiVersion: addons.projectcapsule.dev/v1alpha1
+kind: SopsSecret
+metadata:
+  name: origin
+  namespace: default
+spec:
+  secrets:
+    - name: generated
+      stringData:
+        password: secret
+`), "", filepath.Join(dir, "origin.yaml"))
+	require.NoError(t, err)
+
+	origin := &sopsv1alpha1.SopsSecret{}
+	require.NoError(t, yaml.Unmarshal(encrypted, origin))
+	origin.UID = "origin-uid"
+
+	dec := decryptor.NewSOPSDecryptor("")
+	require.NoError(t, dec.AddAgeKey([]byte(identity.String())))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	reconcile := func(labelGenerated bool) secretOutcome {
+		_, outcome, err := reconcileSecret(ctx, c, logr.Discard(), nil, origin, dec,
+			origin.Spec.Secrets[0].DeepCopy(), "default", sopsv1alpha1.SecretMetadata{},
+			sopsv1alpha1.DriftPolicyReport, labelGenerated,
+		)
+		require.NoError(t, err)
+
+		return outcome
+	}
+
+	require.Equal(t, metrics.SecretOperationCreate, reconcile(false).Operation)
+
+	// The secret drifts, which is only reported
+	require.NoError(t, c.Apply(ctx, corev1ac.Secret("generated", "default").
+		WithData(map[string][]byte{"password": []byte("modified")}),
+		client.FieldOwner("kubectl"),
+		client.ForceOwnership,
+	))
+
+	// Enabling the restricted secret cache mode labels existing secrets,
+	// without repairing the drift
+	outcome := reconcile(true)
+	require.Equal(t, []string{`key "password" modified`}, outcome.Drift)
+	require.Equal(t, metrics.SecretOperationUpdate, outcome.Operation)
+
+	live := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "generated", Namespace: "default"}, live))
+	require.Equal(t, "true", live.Labels[meta.GeneratedSecretLabel])
+	require.Equal(t, []byte("modified"), live.Data["password"])
+
+	// Labeled secrets are not written again
+	outcome = reconcile(true)
+	require.Equal(t, []string{`key "password" modified`}, outcome.Drift)
+	require.Empty(t, outcome.Operation)
+}
@@ -0,0 +1,79 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"hash/fnv"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ShardFilter restricts the SopsSecrets reconciled by a replica, either by
+// labels or by the hash of their name. The zero value selects all SopsSecrets.
+type ShardFilter struct {
+	// Selector for the labels of the selected SopsSecrets
+	Selector labels.Selector
+	// Number of hash based shards, hash based sharding is disabled when zero
+	Count uint32
+	// Index of the hash based shard of the replica
+	Index uint32
+}
+
+// Matches returns true if the object belongs to the shard.
+func (f ShardFilter) Matches(obj metav1.Object) bool {
+	if f.Selector != nil && !f.Selector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+
+	if f.Count == 0 {
+		return true
+	}
+
+	return shardIndex(obj, f.Count) == f.Index
+}
+
+// Only events of SopsSecrets in the shard are queued.
+func (f ShardFilter) predicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return f.Matches(obj)
+	})
+}
+
+// Only events of objects owned by a SopsSecret in the hash based shard are
+// queued. The labels of the owners are unknown, SopsSecrets of other label
+// based shards are not cached and skipped on reconcile.
+func (f ShardFilter) ownedPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		if f.Count == 0 {
+			return true
+		}
+
+		for _, ref := range obj.GetOwnerReferences() {
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil || gv.Group != sopsv1alpha1.GroupVersion.Group || ref.Kind != "SopsSecret" {
+				continue
+			}
+
+			owner := &metav1.ObjectMeta{Name: ref.Name, Namespace: obj.GetNamespace()}
+			if shardIndex(owner, f.Count) == f.Index {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Hash based shard of an object, by namespace and name.
+func shardIndex(obj metav1.Object, count uint32) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(obj.GetNamespace() + "/" + obj.GetName()))
+
+	return h.Sum32() % count
+}
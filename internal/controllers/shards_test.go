@@ -0,0 +1,100 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestShardFilterLabels(t *testing.T) {
+	t.Parallel()
+
+	sharded := &metav1.ObjectMeta{Name: "a", Namespace: "default", Labels: map[string]string{meta.ShardLabel: "shard1"}}
+	unsharded := &metav1.ObjectMeta{Name: "b", Namespace: "default"}
+
+	require.True(t, ShardFilter{}.Matches(sharded))
+	require.True(t, ShardFilter{}.Matches(unsharded))
+
+	shard := ShardFilter{Selector: labels.SelectorFromSet(labels.Set{meta.ShardLabel: "shard1"})}
+	require.True(t, shard.Matches(sharded))
+	require.False(t, shard.Matches(unsharded))
+
+	rest, err := labels.Parse("!" + meta.ShardLabel)
+	require.NoError(t, err)
+	require.False(t, ShardFilter{Selector: rest}.Matches(sharded))
+	require.True(t, ShardFilter{Selector: rest}.Matches(unsharded))
+}
+
+func TestShardFilterHash(t *testing.T) {
+	t.Parallel()
+
+	const count = 3
+
+	shards := make([]ShardFilter, count)
+	for i := range shards {
+		shards[i] = ShardFilter{Count: count, Index: uint32(i)}
+	}
+
+	owned := make([]int, count)
+
+	for i := range 300 {
+		obj := &metav1.ObjectMeta{Name: fmt.Sprintf("secret-%d", i), Namespace: "default"}
+
+		matches := 0
+
+		for j, shard := range shards {
+			if shard.Matches(obj) {
+				matches++
+				owned[j]++
+			}
+		}
+
+		// Each object belongs to exactly one shard
+		require.Equal(t, 1, matches)
+	}
+
+	for _, n := range owned {
+		require.Positive(t, n)
+	}
+}
+
+func TestShardFilterPredicates(t *testing.T) {
+	t.Parallel()
+
+	const count = 2
+
+	secret := &sopsv1alpha1.SopsSecret{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}}
+	index := shardIndex(secret, count)
+
+	owned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:      "generated",
+		Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: sopsv1alpha1.GroupVersion.String(),
+			Kind:       "SopsSecret",
+			Name:       secret.Name,
+		}},
+	}}
+
+	shard := ShardFilter{Count: count, Index: index}
+	require.True(t, shard.predicate().Create(event.CreateEvent{Object: secret}))
+	require.True(t, shard.ownedPredicate().Create(event.CreateEvent{Object: owned}))
+
+	other := ShardFilter{Count: count, Index: (index + 1) % count}
+	require.False(t, other.predicate().Create(event.CreateEvent{Object: secret}))
+	require.False(t, other.ownedPredicate().Create(event.CreateEvent{Object: owned}))
+
+	// Without hash based sharding, owners are not filtered
+	require.True(t, ShardFilter{}.ownedPredicate().Create(event.CreateEvent{Object: owned}))
+}
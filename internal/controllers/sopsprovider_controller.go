@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
@@ -14,7 +15,9 @@ import (
 	"github.com/peak-scale/sops-operator/internal/decryptor"
 	"github.com/peak-scale/sops-operator/internal/meta"
 	"github.com/peak-scale/sops-operator/internal/metrics"
+	"github.com/peak-scale/sops-operator/internal/tracing"
 	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,78 +27,96 @@ import (
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/event"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
-	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// Timeout of Vault token lookups for the key Secret metrics.
+const vaultLookupTimeout = 5 * time.Second
+
 // SopsProviderReconciler reconciles a SopsProvider object.
 type SopsProviderReconciler struct {
 	client.Client
 
-	Metrics  *metrics.Recorder
-	Log      logr.Logger
-	Recorder record.EventRecorder
-	Scheme   *runtime.Scheme
+	// Reads referenced key Secrets, bypassing the cache
+	APIReader client.Reader
+	Metrics   *metrics.Recorder
+	Log       logr.Logger
+	Recorder  record.EventRecorder
+	Scheme    *runtime.Scheme
+	// Namespaces key Secrets are selected from
+	Namespaces NamespaceFilter
+	// Interval to reload referenced key Secrets, changes of unlabeled
+	// Secrets are not watched
+	KeySecretRefsInterval time.Duration
+	// Cache of key Secrets, the manager cache when not set
+	KeySecrets cache.Cache
+
+	keySecrets *keySecretWatch
 }
 
 func (r *SopsProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&sopsv1alpha1.SopsProvider{}, builder.WithPredicates(primaryResourcePredicate())).
-		Watches(
-			&corev1.Secret{},
-			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
-				var list sopsv1alpha1.SopsProviderList
-				if err := r.Client.List(ctx, &list); err != nil {
-					r.Log.Error(err, "unable to list SopsProvider objects")
-
-					return nil
-				}
-
-				var requests []reconcile.Request
-				for _, sp := range list.Items {
-					requests = append(requests, reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      sp.Name,
-							Namespace: sp.Namespace,
-						},
-					})
-				}
-
-				return requests
-			}),
-			builder.WithPredicates(predicate.Funcs{
-				CreateFunc: func(e event.CreateEvent) bool {
-					_, ok := e.Object.GetLabels()[meta.KeySecretLabel]
-
-					return ok
-				},
-				UpdateFunc: func(e event.UpdateEvent) bool {
-					_, oldOk := e.ObjectOld.GetLabels()[meta.KeySecretLabel]
-					_, newOk := e.ObjectNew.GetLabels()[meta.KeySecretLabel]
-
-					return oldOk || newOk
-				},
-				DeleteFunc: func(e event.DeleteEvent) bool {
-					_, ok := e.Object.GetLabels()[meta.KeySecretLabel]
-
-					return ok
-				},
-			}),
-		).
-		Complete(r)
+	bld := ctrl.NewControllerManagedBy(mgr).
+		For(&sopsv1alpha1.SopsProvider{}, builder.WithPredicates(primaryResourcePredicate()))
+
+	// Relabeled namespaces may change the key Secrets selected by any provider
+	bld = r.Namespaces.watch(bld, func(ctx context.Context, _ string) []reconcile.Request {
+		return r.providerRequests(ctx)
+	})
+
+	c, err := bld.Build(r)
+	if err != nil {
+		return err
+	}
+
+	if r.KeySecrets == nil {
+		r.KeySecrets = mgr.GetCache()
+	}
+
+	r.keySecrets = newKeySecretWatch(r.KeySecrets, c, func(ctx context.Context, _ client.Object) []reconcile.Request {
+		return r.providerRequests(ctx)
+	})
+
+	return nil
+}
+
+// Requests for all SopsProviders.
+func (r *SopsProviderReconciler) providerRequests(ctx context.Context) []reconcile.Request {
+	var list sopsv1alpha1.SopsProviderList
+	if err := r.Client.List(ctx, &list); err != nil {
+		r.Log.Error(err, "unable to list SopsProvider objects")
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, sp := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      sp.Name,
+				Namespace: sp.Namespace,
+			},
+		})
+	}
+
+	return requests
 }
 
 func (r *SopsProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("Request.Name", req.Name)
 
+	ctx, span := tracing.Start(ctx, "SopsProviderReconciler.Reconcile", attribute.String("name", req.Name))
+	defer func() { tracing.End(span, err) }()
+
 	instance := &sopsv1alpha1.SopsProvider{}
 
 	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
 		if apierrors.IsNotFound(err) {
-			// Cleanup Metrics
+			// Cleanup Metrics, the object is not populated when not found
+			instance.SetName(req.Name)
+			instance.SetNamespace(req.Namespace)
+
 			r.Metrics.DeleteProvider(instance)
 
 			log.V(5).Info("Request object not found, could have been deleted after reconcile request")
@@ -128,6 +149,10 @@ func (r *SopsProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, reconcileErr
 	}
 
+	if len(instance.Spec.KeySecretRefs) > 0 {
+		return ctrl.Result{RequeueAfter: r.KeySecretRefsInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -136,62 +161,67 @@ func (r *SopsProviderReconciler) reconcile(
 	log logr.Logger,
 	provider *sopsv1alpha1.SopsProvider,
 ) (err error) {
-	labelSelector := &metav1.LabelSelector{
-		MatchExpressions: []metav1.LabelSelectorRequirement{
-			{
-				Key:      meta.KeySecretLabel,
-				Operator: metav1.LabelSelectorOpExists,
-			},
-		},
-	}
+	selectedSecrets := make(map[string]*corev1.Secret)
 
-	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
-	if err != nil {
-		return err
-	}
+	// Key Secrets are only listed for providers selecting them by labels
+	if len(provider.Spec.ProviderSecrets) > 0 {
+		if lerr := r.keySecrets.start(log); lerr != nil {
+			return lerr
+		}
 
-	secretList := &corev1.SecretList{}
-	if err := r.List(ctx, secretList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
-		log.Error(err, "Failed to list secrets")
+		candidates, lerr := listKeySecrets(ctx, r.KeySecrets, log)
+		if lerr != nil {
+			return lerr
+		}
 
-		return err
-	}
+		candidates, lerr = r.Namespaces.filterSecrets(ctx, r.Client, candidates)
+		if lerr != nil {
+			return lerr
+		}
 
-	secretPtrs := make([]*corev1.Secret, 0)
-	for i := range secretList.Items {
-		secretPtrs = append(secretPtrs, &secretList.Items[i])
+		selectedSecrets, err = provider.SelectKeySecrets(ctx, r.Client, candidates)
 	}
 
-	selectedSecrets := make(map[string]*corev1.Secret)
-
-	for _, selector := range provider.Spec.ProviderSecrets {
-		matchingSecrets, merr := api.MatchTypedObjects(ctx, r.Client, selector, secretPtrs)
-		if merr != nil {
-			err = errors.Join(err, merr)
+	// Referenced key Secrets are read from the API server, so only get access
+	// to them is required
+	for _, ref := range provider.Spec.KeySecretRefs {
+		secret, ferr := ref.Fetch(ctx, r.APIReader)
+		if ferr != nil {
+			err = errors.Join(err, ferr)
 
 			continue
 		}
 
-		log.V(4).Info("loading secrets", "total", len(matchingSecrets))
-
-		// Iterate over matched secrets
-		for _, secret := range matchingSecrets {
-			if !secret.DeletionTimestamp.IsZero() {
-				continue
-			}
+		selectedSecrets[string(secret.UID)] = secret
+	}
 
-			selectedSecrets[string(secret.UID)] = secret
-		}
+	if lerr := loadKeySecrets(ctx, log, r.Metrics, provider.Name, &provider.Status, selectedSecrets); lerr != nil {
+		return lerr
 	}
 
+	return err
+}
+
+// Load the keys of the selected key Secrets and record their state in the
+// status of the provider and the metrics.
+func loadKeySecrets(
+	ctx context.Context,
+	log logr.Logger,
+	recorder *metrics.Recorder,
+	providerName string,
+	status *sopsv1alpha1.SopsProviderStatus,
+	selectedSecrets map[string]*corev1.Secret,
+) error {
+	log.V(4).Info("loading secrets", "total", len(selectedSecrets))
+
 	for key, secret := range selectedSecrets {
 		log.V(7).Info("selected secret", "key", key, "type", secret.Type)
 	}
 
 	// Run Garbage Collection (Removes items which are no longer selected)
-	for _, secret := range provider.Status.Providers {
+	for _, secret := range status.Providers {
 		if _, ok := selectedSecrets[string(secret.UID)]; !ok {
-			provider.Status.RemoveInstance(&sopsv1alpha1.SopsProviderItemStatus{
+			status.RemoveInstance(&sopsv1alpha1.SopsProviderItemStatus{
 				Origin: secret.Origin,
 			})
 		}
@@ -208,27 +238,73 @@ func (r *SopsProviderReconciler) reconcile(
 	// Update Each Secret
 	failed := false
 
+	recorder.DeleteProviderKeySecrets(providerName)
+
 	for _, sec := range selectedSecrets {
-		status := &sopsv1alpha1.SopsProviderItemStatus{
+		item := &sopsv1alpha1.SopsProviderItemStatus{
 			Origin: *api.NewOrigin(sec),
 		}
 
-		if decError := decryptor.KeysFromSecret(ctx, r.Client, sec.Name, sec.Namespace); decError != nil {
-			status.Condition = meta.NewNotReadyCondition(sec, decError.Error())
+		decError := decryptor.KeysFromData(sec.Data, fmt.Sprintf("decryption Secret '%s'", sec.Name))
+		if decError != nil {
+			item.Condition = meta.NewNotReadyCondition(sec, decError.Error())
 
 			failed = true
 		} else {
-			status.Condition = meta.NewReadyCondition(sec)
+			item.Condition = meta.NewReadyCondition(sec)
 		}
 
-		provider.Status.UpdateInstance(status)
+		status.UpdateInstance(item)
+
+		recorder.RecordProviderKeySecret(providerName, sec, keySecretState(ctx, log, sec, decError == nil))
 	}
 
 	if failed {
 		return fmt.Errorf("failed loading secret(s)")
 	}
 
-	return err
+	return nil
+}
+
+// State of a key Secret for the metrics. The Vault token is looked up when a
+// Vault address is configured.
+func keySecretState(
+	ctx context.Context,
+	log logr.Logger,
+	secret *corev1.Secret,
+	loaded bool,
+) metrics.KeySecretState {
+	state := metrics.KeySecretState{Loaded: loaded}
+
+	inventory, err := decryptor.InspectKeys(secret.Data)
+	if err != nil {
+		log.V(5).Info("cannot inspect key secret", "secret", secret.Name, "namespace", secret.Namespace, "error", err.Error())
+
+		return state
+	}
+
+	state.Identities = inventory.Identities
+	state.PGPExpiry = inventory.PGPExpiry
+
+	if inventory.VaultToken == "" || !decryptor.VaultAddressConfigured() {
+		return state
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, vaultLookupTimeout)
+	defer cancel()
+
+	expiry, ok, err := decryptor.VaultTokenExpiry(lookupCtx, inventory.VaultToken)
+	if err != nil {
+		log.V(5).Info("cannot look up vault token", "secret", secret.Name, "namespace", secret.Namespace, "error", err.Error())
+
+		return state
+	}
+
+	if ok {
+		state.VaultTokenExpiry = expiry
+	}
+
+	return state
 }
 
 func (r *SopsProviderReconciler) updateStatus(
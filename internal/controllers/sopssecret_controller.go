@@ -11,9 +11,12 @@ import (
 	"github.com/go-logr/logr"
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
 	errs "github.com/peak-scale/sops-operator/internal/api/errors"
+	"github.com/peak-scale/sops-operator/internal/audit"
 	"github.com/peak-scale/sops-operator/internal/meta"
 	"github.com/peak-scale/sops-operator/internal/metrics"
+	"github.com/peak-scale/sops-operator/internal/tracing"
 	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,17 +35,28 @@ type SopsSecretReconcilerConfig struct {
 	EnableStatus          bool
 	ControllerName        string
 	FailedSecretsInterval metav1.Duration
+	// Don't use SopsProviders for decryption, only NamespacedSopsProviders
+	DisableClusterProviders bool
+	// Namespaces SopsSecrets are reconciled in
+	Namespaces NamespaceFilter
+	// Shard of SopsSecrets reconciled by this replica
+	Shard ShardFilter
+	// Label generated secrets for the restricted secret cache mode
+	RestrictSecretCache bool
 }
 
 // SopsSecretReconciler reconciles a SopsSecret object.
 type SopsSecretReconciler struct {
 	client.Client
 
-	Metrics  *metrics.Recorder
-	Log      logr.Logger
-	Recorder record.EventRecorder
-	Scheme   *runtime.Scheme
-	Config   SopsSecretReconcilerConfig
+	// Reads referenced key Secrets, bypassing the cache
+	APIReader client.Reader
+	Metrics   *metrics.Recorder
+	Audit     *audit.Logger
+	Log       logr.Logger
+	Recorder  record.EventRecorder
+	Scheme    *runtime.Scheme
+	Config    SopsSecretReconcilerConfig
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -51,48 +65,105 @@ func (r *SopsSecretReconciler) SetupWithManager(mgr ctrl.Manager, cfg SopsSecret
 
 	r.Log.V(7).Info("controller config", "config", r.Config)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	bld := ctrl.NewControllerManagedBy(mgr).
 		Named(cfg.ControllerName).
-		For(&sopsv1alpha1.SopsSecret{}, builder.WithPredicates(primaryResourcePredicate())).
+		For(&sopsv1alpha1.SopsSecret{}, builder.WithPredicates(primaryResourcePredicate(), cfg.Shard.predicate())).
 		Watches(&corev1.Secret{},
-			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &sopsv1alpha1.SopsSecret{})).
-		Watches(
+			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &sopsv1alpha1.SopsSecret{}),
+			builder.WithPredicates(cfg.Shard.ownedPredicate()))
+
+	bld = cfg.Namespaces.watch(bld, func(ctx context.Context, namespace string) []reconcile.Request {
+		return r.secretRequests(ctx, client.InNamespace(namespace))
+	})
+
+	if !cfg.DisableClusterProviders {
+		bld = bld.Watches(
 			&sopsv1alpha1.SopsProvider{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
-				var list sopsv1alpha1.SopsSecretList
-				if err := r.Client.List(ctx, &list); err != nil {
-					r.Log.Error(err, "unable to list SopsSecrets")
+				return r.secretRequests(ctx)
+			}),
+			builder.WithPredicates(sopsProviderStatusPredicate()),
+		)
+	}
 
+	return bld.
+		Watches(
+			&sopsv1alpha1.NamespacedSopsProvider{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				provider, ok := obj.(*sopsv1alpha1.NamespacedSopsProvider)
+				if !ok {
 					return nil
 				}
 
-				var requests []reconcile.Request
-				for _, s := range list.Items {
-					requests = append(requests, reconcile.Request{
-						NamespacedName: types.NamespacedName{
-							Name:      s.Name,
-							Namespace: s.Namespace,
-						},
-					})
+				// Providers for tenant namespaces may select SopsSecrets in any namespace
+				opts := []client.ListOption{}
+				if !provider.Spec.TenantNamespaces {
+					opts = append(opts, client.InNamespace(provider.Namespace))
 				}
 
-				return requests
+				return r.secretRequests(ctx, opts...)
 			}),
 			builder.WithPredicates(sopsProviderStatusPredicate()),
 		).
 		Complete(r)
 }
 
+// Requests for the SopsSecrets listed with opts, in the shard of the replica.
+func (r *SopsSecretReconciler) secretRequests(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	var list sopsv1alpha1.SopsSecretList
+	if err := r.Client.List(ctx, &list, opts...); err != nil {
+		r.Log.Error(err, "unable to list SopsSecrets")
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, s := range list.Items {
+		if !r.Config.Shard.Matches(&s) {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      s.Name,
+				Namespace: s.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
+
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.16.3/pkg/reconcile
 func (r *SopsSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
 	log := r.Log.WithValues("Request.Name", req.Name)
 
+	ctx, span := tracing.Start(ctx, "SopsSecretReconciler.Reconcile",
+		attribute.String("namespace", req.Namespace),
+		attribute.String("name", req.Name),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	selected, err := r.Config.Namespaces.Matches(ctx, r.Client, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !selected {
+		log.V(5).Info("namespace not selected, skipping")
+
+		return ctrl.Result{}, nil
+	}
+
 	instance := &sopsv1alpha1.SopsSecret{}
 
 	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
 		if apierrors.IsNotFound(err) {
-			// Cleanup Metrics
+			// Cleanup Metrics, the object is not populated when not found
+			instance.SetName(req.Name)
+			instance.SetNamespace(req.Namespace)
+
 			r.Metrics.DeleteSecret(instance)
 			log.V(5).Info("Request object not found, could have been deleted after reconcile request")
 
@@ -104,6 +175,15 @@ func (r *SopsSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return reconcile.Result{}, nil
 	}
 
+	// Merged secrets are not owned, remove the merged keys before releasing the object
+	if !instance.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, finalizeMergedSecrets(ctx, r.Client, instance, &instance.Status)
+	}
+
+	if err := reconcileMergeFinalizer(ctx, r.Client, instance, &instance.Status, instance.Spec.Secrets); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Main Reconciler
 	reconcileErr := r.reconcile(
 		ctx,
@@ -145,7 +225,9 @@ func (r *SopsSecretReconciler) reconcile(
 	// Load Decryption Provider (Keys)
 	log.V(5).Info("loading secrets provider")
 
-	sopsFormat, provider, cleanup, err := fetchDecryptionProviders(ctx, r.Client, log, r.Config, &secret.Status, secret)
+	sopsFormat, provider, providers, cleanup, err := fetchDecryptionProviders(ctx, r.Client, r.APIReader, log, r.Config, r.Metrics, &secret.Status, secret)
+
+	auditor := r.Audit.For(secret, sopsv1alpha1.GroupVersion.WithKind("SopsSecret"), providers)
 
 	defer func() {
 		if cleanup != nil {
@@ -158,6 +240,9 @@ func (r *SopsSecretReconciler) reconcile(
 		return cleanupSecrets(
 			ctx,
 			r.Client,
+			r.Metrics,
+			auditor,
+			secret,
 			&secret.Status,
 		)
 	}
@@ -165,21 +250,26 @@ func (r *SopsSecretReconciler) reconcile(
 	// Iterate over Secrets
 	selectedSecrets := make(map[string]bool)
 
+	drifted := make([]string, 0)
+
 	failed := false
 
 	for _, sec := range secret.Spec.Secrets {
 		slog := log.WithValues("secret", sec.Name)
 
 		// Reconcile Secret
-		target, serr := reconcileSecret(
+		target, outcome, serr := reconcileSecret(
 			ctx,
 			r.Client,
 			slog,
+			auditor,
 			sopsFormat,
 			provider,
 			sec,
 			secret.Namespace,
 			secret.Spec.Metadata,
+			secret.Spec.DriftPolicy,
+			r.Config.RestrictSecretCache,
 		)
 
 		selectedSecrets[target.Name+"/"+target.Namespace] = true
@@ -187,37 +277,55 @@ func (r *SopsSecretReconciler) reconcile(
 		if serr != nil {
 			failed = true
 
-			secret.Status.UpdateInstance(
-				meta.NewNotReadySecretStatusCondition(target, serr.Error()),
-			)
+			status := meta.NewNotReadySecretStatusCondition(target, serr.Error())
+			status.Mode = sec.Mode.OrDefault()
+
+			secret.Status.UpdateInstance(status)
 
 			continue
 		}
 
-		secret.Status.UpdateInstance(
-			meta.NewReadySecretStatusCondition(target),
-		)
+		if outcome.Operation != "" {
+			r.Metrics.RecordSecretOperation(outcome.Operation)
+			auditor.SecretWritten(ctx, sec.Name, outcome.Operation, target, outcome.Checksum, outcome.Recipients)
+		}
+
+		if len(outcome.Drift) > 0 {
+			drifted = append(drifted, target.Namespace+"/"+target.Name)
+		}
+
+		status := meta.NewReadySecretStatusCondition(target)
+		status.Mode = sec.Mode.OrDefault()
+		status.Conflicts = outcome.Conflicts
+
+		secret.Status.UpdateInstance(status)
 	}
 
+	secret.Status.Conditions.UpdateConditionByType(meta.NewDriftedCondition(
+		secret,
+		secret.Status.Conditions,
+		drifted,
+		secret.Spec.DriftPolicy.OrDefault() != sopsv1alpha1.DriftPolicyReport,
+	))
+
 	// Lifecycle Secrets
 	for _, sec := range secret.Status.Secrets {
 		if _, ok := selectedSecrets[sec.Name+"/"+sec.Namespace]; !ok {
 			log.V(7).Info("garbage collection", "secret", sec.Name, "namespace", sec.Namespace)
 
-			err := r.Delete(ctx, &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      sec.Name,
-					Namespace: sec.Namespace,
-				},
-			})
-			if err != nil && !apierrors.IsNotFound(err) {
+			if err := removeSecret(ctx, r.Client, secret, sec); err != nil {
 				failed = true
 
+				auditor.SecretRemoved(ctx, removeOperation(sec), sec.Namespace, sec.Name, err)
+
 				log.Error(err, "error removing secret")
 
 				continue
 			}
 
+			r.Metrics.RecordSecretOperation(removeOperation(sec))
+			auditor.SecretRemoved(ctx, removeOperation(sec), sec.Namespace, sec.Name, nil)
+
 			// Remove Instance
 			secret.Status.RemoveInstance(&sopsv1alpha1.SopsSecretItemStatus{
 				Name:      sec.Name,
@@ -0,0 +1,151 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// iniDefaultSection is the section holding keys declared before any section.
+const iniDefaultSection = "DEFAULT"
+
+// FormatFor returns the format of a document. When no format is declared, it's
+// derived from the extension of the key the document is stored in.
+func FormatFor(key string, format string) formats.Format {
+	return formats.FormatForPathOrString(key, format)
+}
+
+// DecryptDocument decrypts a standalone SOPS encrypted document and expands it
+// into secret data:
+//   - YAML, JSON and dotenv: Each top-level entry becomes a key. Values which
+//     are not strings are stored JSON encoded.
+//   - INI: Each entry becomes a key named <section>.<key>. Entries of the
+//     default section are stored without a prefix.
+//   - Binary: The document is stored under the given key.
+//
+// All keys must be valid secret keys.
+func (d *SOPSDecryptor) DecryptDocument(
+	ctx context.Context,
+	data []byte,
+	format formats.Format,
+	key string,
+	log logr.Logger,
+) (map[string][]byte, error) {
+	if int64(len(data)) > d.maxFileSize {
+		return nil, fmt.Errorf("cannot decrypt %s document: exceeds maximum size of %d bytes", sopsFormatToString[format], d.maxFileSize)
+	}
+
+	if format == formats.Binary {
+		out, err := d.SopsDecryptWithFormat(ctx, data, log, formats.Binary, formats.Binary)
+		if err != nil {
+			return nil, err
+		}
+
+		return validateKeys(map[string][]byte{key: out})
+	}
+
+	out, err := d.SopsDecryptWithFormat(ctx, data, log, format, formats.Json)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("decrypted %s document is not a map: %w", sopsFormatToString[format], err)
+	}
+
+	var expanded map[string][]byte
+	if format == formats.Ini {
+		expanded, err = expandSections(document)
+	} else {
+		expanded, err = expandEntries(document, "")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return validateKeys(expanded)
+}
+
+// Ensure all keys of the expanded document are valid secret keys.
+func validateKeys(data map[string][]byte) (map[string][]byte, error) {
+	var errs []error
+
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		if msgs := validation.IsConfigMapKey(key); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid secret key %q: %s", key, strings.Join(msgs, ", ")))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return data, nil
+}
+
+// Expand the entries of an INI document.
+func expandSections(document map[string]any) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+
+	for section, value := range document {
+		entries, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("section %q is not a map", section)
+		}
+
+		prefix := section + "."
+		if section == iniDefaultSection {
+			prefix = ""
+		}
+
+		expanded, err := expandEntries(entries, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		maps.Copy(result, expanded)
+	}
+
+	return result, nil
+}
+
+// Expand the top-level entries of a document.
+func expandEntries(document map[string]any, prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte, len(document))
+
+	for key, value := range document {
+		switch v := value.(type) {
+		case string:
+			result[prefix+key] = []byte(v)
+		case json.Number:
+			result[prefix+key] = []byte(v.String())
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("cannot encode value of %q: %w", prefix+key, err)
+			}
+
+			result[prefix+key] = encoded
+		}
+	}
+
+	return result, nil
+}
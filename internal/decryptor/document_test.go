@@ -0,0 +1,108 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptDocument(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		file   string
+		format string
+		key    string
+		want   map[string][]byte
+	}{
+		"yaml": {
+			file: "testdata/secret-age.yaml",
+			key:  "secret.yaml",
+			want: map[string][]byte{
+				"apiVersion": []byte("v1"),
+				"kind":       []byte("Secret"),
+				"metadata":   []byte(`{"name":"sops-age"}`),
+				"stringData": []byte(`{"database_password":"VERY_SECRET","database_user":"MUCH_SECURE"}`),
+			},
+		},
+		"dotenv": {
+			file: "testdata/document.env",
+			key:  "app.env",
+			want: map[string][]byte{
+				"DATABASE_USER":     []byte("admin"),
+				"DATABASE_PASSWORD": []byte("secret"),
+			},
+		},
+		"ini": {
+			file:   "testdata/document.ini",
+			format: "ini",
+			key:    "config",
+			want: map[string][]byte{
+				"token":             []byte("global"),
+				"database.user":     []byte("admin"),
+				"database.password": []byte("secret"),
+			},
+		},
+		"binary": {
+			file: "testdata/document.bin",
+			key:  "payload",
+			want: map[string][]byte{
+				"payload": []byte("binary-content\n"),
+			},
+		},
+	}
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			d := NewSOPSDecryptor("")
+			require.NoError(t, d.AddAgeKey(key))
+
+			document, err := os.ReadFile(tt.file)
+			require.NoError(t, err)
+
+			data, err := d.DecryptDocument(context.Background(), document, FormatFor(tt.key, tt.format), tt.key, logr.Discard())
+			require.NoError(t, err)
+			require.Equal(t, tt.want, data)
+		})
+	}
+}
+
+func TestDecryptDocumentWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	document, err := os.ReadFile("testdata/document.env")
+	require.NoError(t, err)
+
+	_, err = NewSOPSDecryptor("").DecryptDocument(context.Background(), document, formats.Dotenv, "app.env", logr.Discard())
+	require.Error(t, err)
+}
+
+func TestValidateKeys(t *testing.T) {
+	t.Parallel()
+
+	_, err := validateKeys(map[string][]byte{
+		"DATABASE_USER":   []byte("admin"),
+		"database.user-1": []byte("admin"),
+	})
+	require.NoError(t, err)
+
+	_, err = validateKeys(map[string][]byte{
+		"valid":      []byte("value"),
+		"not valid":  []byte("value"),
+		"also/wrong": []byte("value"),
+	})
+	require.ErrorContains(t, err, `invalid secret key "also/wrong"`)
+	require.ErrorContains(t, err, `invalid secret key "not valid"`)
+}
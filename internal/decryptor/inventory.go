@@ -0,0 +1,127 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/vault/api"
+	"github.com/peak-scale/sops-operator/internal/decryptor/kustomize-controller/age"
+)
+
+// KeyInventory describes the keys and credentials in the data of a decryption
+// Secret.
+type KeyInventory struct {
+	// Identities counts the private keys (age, pgp) and credentials (hc_vault,
+	// aws_kms, azure_kv, gcp_kms) by key type.
+	Identities map[string]int
+	// PGPExpiry is the expiry of the PGP keys by fingerprint. Keys without
+	// expiry are omitted.
+	PGPExpiry map[string]time.Time
+	// VaultToken is the Hashicorp Vault token, if any.
+	VaultToken string
+}
+
+// InspectKeys returns the inventory of the keys and credentials, using the same
+// naming as the data of a decryption Secret. Entries with other names are
+// skipped.
+func InspectKeys(data map[string][]byte) (*KeyInventory, error) {
+	inventory := &KeyInventory{
+		Identities: make(map[string]int),
+		PGPExpiry:  make(map[string]time.Time),
+	}
+
+	for name, value := range data {
+		switch {
+		case filepath.Ext(name) == DecryptionAgeExt:
+			var identities age.ParsedIdentities
+			if err := identities.Import(string(value)); err != nil {
+				return nil, fmt.Errorf("failed to inspect %s: %w", name, err)
+			}
+
+			inventory.Identities[KeyTypeAge] += len(identities)
+		case filepath.Ext(name) == DecryptionPGPExt:
+			entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(value))
+			if err != nil {
+				return nil, fmt.Errorf("failed to inspect %s: %w", name, err)
+			}
+
+			inventory.Identities[KeyTypePGP] += len(entities)
+
+			for _, entity := range entities {
+				if expiry, ok := pgpExpiry(entity); ok {
+					inventory.PGPExpiry[strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint))] = expiry
+				}
+			}
+		case name == DecryptionVaultTokenFileName:
+			inventory.Identities[KeyTypeVault]++
+			inventory.VaultToken = string(bytes.TrimSpace(value))
+		case name == DecryptionAWSKmsFile:
+			inventory.Identities[KeyTypeAWSKMS]++
+		case name == DecryptionAzureAuthFile:
+			inventory.Identities[KeyTypeAzureKV]++
+		case name == DecryptionGCPCredsFile:
+			inventory.Identities[KeyTypeGCPKMS]++
+		}
+	}
+
+	return inventory, nil
+}
+
+// Expiry of the primary key of a PGP entity, false if it does not expire.
+func pgpExpiry(entity *openpgp.Entity) (time.Time, bool) {
+	identity := entity.PrimaryIdentity()
+	if identity == nil || identity.SelfSignature == nil {
+		return time.Time{}, false
+	}
+
+	lifetime := identity.SelfSignature.KeyLifetimeSecs
+	if lifetime == nil || *lifetime == 0 {
+		return time.Time{}, false
+	}
+
+	return entity.PrimaryKey.CreationTime.Add(time.Duration(*lifetime) * time.Second), true
+}
+
+// VaultAddressConfigured returns true when a Vault address to look up tokens
+// is configured with the VAULT_ADDR environment variable.
+func VaultAddressConfigured() bool {
+	return os.Getenv(api.EnvVaultAddress) != ""
+}
+
+// VaultTokenExpiry looks up the expiry of the token at the Vault server
+// configured with the VAULT_ADDR environment variable. Tokens without TTL
+// return false.
+func VaultTokenExpiry(ctx context.Context, token string) (time.Time, bool, error) {
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("cannot create Vault client: %w", err)
+	}
+
+	client.SetToken(token)
+
+	secret, err := client.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("cannot look up Vault token: %w", err)
+	}
+
+	ttl, err := secret.TokenTTL()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("cannot read Vault token TTL: %w", err)
+	}
+
+	if ttl == 0 {
+		return time.Time{}, false, nil
+	}
+
+	return time.Now().Add(ttl), true, nil
+}
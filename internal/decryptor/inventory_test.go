@@ -0,0 +1,69 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectKeys(t *testing.T) {
+	t.Parallel()
+
+	ageKey, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	pgpKey, err := os.ReadFile("testdata/pgp.asc")
+	require.NoError(t, err)
+
+	expiring, err := openpgp.NewEntity("expiring", "", "expiring@example.com", &packet.Config{
+		KeyLifetimeSecs: 3600,
+	})
+	require.NoError(t, err)
+
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, expiring.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	inventory, err := InspectKeys(map[string][]byte{
+		"identity.agekey":            ageKey,
+		"identity.asc":               pgpKey,
+		"expiring.asc":               armored.Bytes(),
+		DecryptionVaultTokenFileName: []byte("token\n"),
+		DecryptionAWSKmsFile:         []byte("aws_access_key_id: id"),
+		"unrelated":                  []byte("value"),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]int{
+		KeyTypeAge:    1,
+		KeyTypePGP:    2,
+		KeyTypeVault:  1,
+		KeyTypeAWSKMS: 1,
+	}, inventory.Identities)
+	require.Equal(t, "token", inventory.VaultToken)
+
+	fingerprint := strings.ToUpper(hex.EncodeToString(expiring.PrimaryKey.Fingerprint))
+	require.Equal(t, map[string]time.Time{
+		fingerprint: expiring.PrimaryKey.CreationTime.Add(time.Hour),
+	}, inventory.PGPExpiry)
+}
+
+func TestInspectKeysInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := InspectKeys(map[string][]byte{"identity.agekey": []byte("invalid")})
+	require.Error(t, err)
+}
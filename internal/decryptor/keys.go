@@ -0,0 +1,116 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// KeysFromPath imports the keys at path. The path may be a key file named like
+// the entries of a decryption Secret (*.agekey, *.asc, sops.vault-token, ...),
+// a directory of such files or a Secret manifest (.yaml, .yml or .json).
+func (d *SOPSDecryptor) KeysFromPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat keys %q: %w", path, err)
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return fmt.Errorf("read keys %q: %w", path, err)
+		}
+
+		data := make(map[string][]byte, len(entries))
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			content, err := os.ReadFile(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("read key %q: %w", entry.Name(), err)
+			}
+
+			data[entry.Name()] = content
+		}
+
+		return d.KeysFromData(data, fmt.Sprintf("directory '%s'", path))
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read keys %q: %w", path, err)
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+		data, err := secretData(content)
+		if err != nil {
+			return fmt.Errorf("read keys %q: %w", path, err)
+		}
+
+		return d.KeysFromData(data, fmt.Sprintf("Secret manifest '%s'", path))
+	default:
+		return d.KeysFromData(map[string][]byte{filepath.Base(path): content}, fmt.Sprintf("file '%s'", path))
+	}
+}
+
+// Data of all Secrets in a (multi-document) manifest.
+func secretData(content []byte) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	found := false
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+
+	for {
+		var secret struct {
+			Kind       string            `yaml:"kind"`
+			Data       map[string]string `yaml:"data"`
+			StringData map[string]string `yaml:"stringData"`
+		}
+
+		if err := decoder.Decode(&secret); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		if secret.Kind != "Secret" {
+			continue
+		}
+
+		found = true
+
+		for name, value := range secret.Data {
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("decode %q: %w", name, err)
+			}
+
+			data[name] = decoded
+		}
+
+		for name, value := range secret.StringData {
+			data[name] = []byte(value)
+		}
+	}
+
+	if !found {
+		return nil, errors.New("no Secret found")
+	}
+
+	return data, nil
+}
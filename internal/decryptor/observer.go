@@ -0,0 +1,141 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/getsops/sops/v3/keyservice"
+	"google.golang.org/grpc"
+)
+
+// Key types reported to the Observer.
+const (
+	KeyTypeAge     = "age"
+	KeyTypePGP     = "pgp"
+	KeyTypeVault   = "hc_vault"
+	KeyTypeAWSKMS  = "aws_kms"
+	KeyTypeAzureKV = "azure_kv"
+	KeyTypeGCPKMS  = "gcp_kms"
+	KeyTypeHCKMS   = "hckms"
+	// KeyTypeNone is reported for decryptions which failed before any key
+	// decrypted the data key.
+	KeyTypeNone = "none"
+)
+
+// Reasons of failed decryptions reported to the Observer.
+const (
+	// FailureReasonLoad is reported when the encrypted data can not be parsed.
+	FailureReasonLoad = "load"
+	// FailureReasonDataKey is reported when no key decrypted the data key.
+	FailureReasonDataKey = "data_key"
+	// FailureReasonDecrypt is reported when the values can not be decrypted
+	// with the data key.
+	FailureReasonDecrypt = "decrypt"
+	// FailureReasonMAC is reported when the MAC verification fails.
+	FailureReasonMAC = "mac"
+	// FailureReasonEmit is reported when the decrypted data can not be emitted
+	// in the output format.
+	FailureReasonEmit = "emit"
+)
+
+// Observer is notified about decryptions and key service calls, e.g. to record
+// metrics.
+type Observer interface {
+	// ObserveDecryption is called for each decryption with the type of the key
+	// which decrypted the data key and the failure reason, which is empty for
+	// successful decryptions.
+	ObserveDecryption(keyType string, duration time.Duration, reason string)
+	// ObserveKeyServiceCall is called for each data key decryption attempt
+	// with a key.
+	ObserveKeyServiceCall(keyType string, duration time.Duration, err error)
+}
+
+// SetObserver sets the Observer notified about decryptions.
+func (d *SOPSDecryptor) SetObserver(observer Observer) {
+	d.observer = observer
+}
+
+// Key service client reporting the calls to an Observer. It remembers the type
+// of the last key which decrypted a data key.
+type observedKeyService struct {
+	keyservice.KeyServiceClient
+
+	observer Observer
+	keyType  string
+}
+
+// Wraps the key services for a single decryption. Data keys are decrypted
+// sequentially, so the wrappers are not shared.
+func observeKeyServices(services []keyservice.KeyServiceClient, observer Observer) []keyservice.KeyServiceClient {
+	if observer == nil {
+		return services
+	}
+
+	observed := make([]keyservice.KeyServiceClient, 0, len(services))
+
+	for _, service := range services {
+		observed = append(observed, &observedKeyService{
+			KeyServiceClient: service,
+			observer:         observer,
+		})
+	}
+
+	return observed
+}
+
+func (s *observedKeyService) Decrypt(
+	ctx context.Context,
+	req *keyservice.DecryptRequest,
+	opts ...grpc.CallOption,
+) (*keyservice.DecryptResponse, error) {
+	keyType := KeyTypeOf(req.GetKey())
+	start := time.Now()
+
+	resp, err := s.KeyServiceClient.Decrypt(ctx, req, opts...)
+
+	s.observer.ObserveKeyServiceCall(keyType, time.Since(start), err)
+
+	if err == nil {
+		s.keyType = keyType
+	}
+
+	return resp, err
+}
+
+// Type of the key which decrypted a data key, KeyTypeNone if none did.
+func decryptedKeyType(services []keyservice.KeyServiceClient) string {
+	keyType := KeyTypeNone
+
+	for _, service := range services {
+		if observed, ok := service.(*observedKeyService); ok && observed.keyType != "" {
+			keyType = observed.keyType
+		}
+	}
+
+	return keyType
+}
+
+// KeyTypeOf returns the type of a key service key.
+func KeyTypeOf(key *keyservice.Key) string {
+	switch key.GetKeyType().(type) {
+	case *keyservice.Key_AgeKey:
+		return KeyTypeAge
+	case *keyservice.Key_PgpKey:
+		return KeyTypePGP
+	case *keyservice.Key_VaultKey:
+		return KeyTypeVault
+	case *keyservice.Key_KmsKey:
+		return KeyTypeAWSKMS
+	case *keyservice.Key_AzureKeyvaultKey:
+		return KeyTypeAzureKV
+	case *keyservice.Key_GcpKmsKey:
+		return KeyTypeGCPKMS
+	case *keyservice.Key_HckmsKey:
+		return KeyTypeHCKMS
+	default:
+		return KeyTypeNone
+	}
+}
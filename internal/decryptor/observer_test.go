@@ -0,0 +1,82 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+type observation struct {
+	keyType string
+	reason  string
+	err     error
+}
+
+type testObserver struct {
+	decryptions []observation
+	calls       []observation
+}
+
+func (o *testObserver) ObserveDecryption(keyType string, _ time.Duration, reason string) {
+	o.decryptions = append(o.decryptions, observation{keyType: keyType, reason: reason})
+}
+
+func (o *testObserver) ObserveKeyServiceCall(keyType string, _ time.Duration, err error) {
+	o.calls = append(o.calls, observation{keyType: keyType, err: err})
+}
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	document, err := os.ReadFile("testdata/secret-age.yaml")
+	require.NoError(t, err)
+
+	observer := &testObserver{}
+
+	d := NewSOPSDecryptor("")
+	require.NoError(t, d.AddAgeKey(key))
+	d.SetObserver(observer)
+
+	_, err = d.SopsDecryptWithFormat(context.Background(), document, logr.Discard(), formats.Yaml, formats.Yaml)
+	require.NoError(t, err)
+
+	_, err = d.SopsDecryptWithFormat(context.Background(), []byte("not: encrypted"), logr.Discard(), formats.Yaml, formats.Yaml)
+	require.Error(t, err)
+
+	require.Equal(t, []observation{
+		{keyType: KeyTypeAge},
+		{keyType: KeyTypeNone, reason: FailureReasonLoad},
+	}, observer.decryptions)
+	require.Equal(t, []observation{{keyType: KeyTypeAge}}, observer.calls)
+}
+
+func TestObserverWithoutKey(t *testing.T) {
+	t.Parallel()
+
+	document, err := os.ReadFile("testdata/secret-age.yaml")
+	require.NoError(t, err)
+
+	observer := &testObserver{}
+
+	d := NewSOPSDecryptor("")
+	d.SetObserver(observer)
+
+	_, err = d.SopsDecryptWithFormat(context.Background(), document, logr.Discard(), formats.Yaml, formats.Yaml)
+	require.Error(t, err)
+
+	require.Equal(t, []observation{{keyType: KeyTypeNone, reason: FailureReasonDataKey}}, observer.decryptions)
+	require.Len(t, observer.calls, 1)
+	require.Equal(t, KeyTypeAge, observer.calls[0].keyType)
+	require.Error(t, observer.calls[0].err)
+}
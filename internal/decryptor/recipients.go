@@ -0,0 +1,96 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/getsops/sops/v3/keyservice"
+)
+
+// Recipient identifies the key which decrypted a data key.
+type Recipient struct {
+	// Type of the key, one of the KeyType constants.
+	Type string `json:"type"`
+	// ID is the age recipient, the PGP fingerprint or the reference of the
+	// KMS key.
+	ID string `json:"id"`
+}
+
+// RecipientOf returns the recipient of a key service key.
+func RecipientOf(key *keyservice.Key) Recipient {
+	recipient := Recipient{Type: KeyTypeOf(key)}
+
+	switch k := key.GetKeyType().(type) {
+	case *keyservice.Key_AgeKey:
+		recipient.ID = k.AgeKey.GetRecipient()
+	case *keyservice.Key_PgpKey:
+		recipient.ID = strings.ToUpper(k.PgpKey.GetFingerprint())
+	case *keyservice.Key_VaultKey:
+		recipient.ID = k.VaultKey.GetVaultAddress() + "/v1/" + k.VaultKey.GetEnginePath() + "/keys/" + k.VaultKey.GetKeyName()
+	case *keyservice.Key_KmsKey:
+		recipient.ID = k.KmsKey.GetArn()
+	case *keyservice.Key_AzureKeyvaultKey:
+		recipient.ID = k.AzureKeyvaultKey.GetVaultUrl() + "/keys/" + k.AzureKeyvaultKey.GetName() + "/" + k.AzureKeyvaultKey.GetVersion()
+	case *keyservice.Key_GcpKmsKey:
+		recipient.ID = k.GcpKmsKey.GetResourceId()
+	case *keyservice.Key_HckmsKey:
+		recipient.ID = k.HckmsKey.GetKeyId()
+	}
+
+	return recipient
+}
+
+// Recipients collects the recipients which decrypted data keys.
+type Recipients struct {
+	mu         sync.Mutex
+	recipients []Recipient
+}
+
+type recipientsKey struct{}
+
+// WithRecipients returns a context collecting the recipients of the
+// decryptions using it.
+func WithRecipients(ctx context.Context) (context.Context, *Recipients) {
+	recipients := &Recipients{}
+
+	return context.WithValue(ctx, recipientsKey{}, recipients), recipients
+}
+
+// List returns the distinct recipients, sorted by type and ID.
+func (r *Recipients) List() []Recipient {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.recipients)
+}
+
+func (r *Recipients) add(recipient Recipient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.Contains(r.recipients, recipient) {
+		return
+	}
+
+	r.recipients = append(r.recipients, recipient)
+
+	slices.SortFunc(r.recipients, func(a, b Recipient) int {
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+// Records the recipient of a key in the recipients of the context, if any.
+func recordRecipient(ctx context.Context, key *keyservice.Key) {
+	if recipients, ok := ctx.Value(recipientsKey{}).(*Recipients); ok {
+		recipients.add(RecipientOf(key))
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipientOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key      *keyservice.Key
+		expected Recipient
+	}{
+		{
+			key:      &keyservice.Key{KeyType: &keyservice.Key_AgeKey{AgeKey: &keyservice.AgeKey{Recipient: "age1example"}}},
+			expected: Recipient{Type: KeyTypeAge, ID: "age1example"},
+		},
+		{
+			key:      &keyservice.Key{KeyType: &keyservice.Key_PgpKey{PgpKey: &keyservice.PgpKey{Fingerprint: "b2a4c1"}}},
+			expected: Recipient{Type: KeyTypePGP, ID: "B2A4C1"},
+		},
+		{
+			key: &keyservice.Key{KeyType: &keyservice.Key_VaultKey{VaultKey: &keyservice.VaultKey{
+				VaultAddress: "https://vault:8200",
+				EnginePath:   "sops",
+				KeyName:      "tenant",
+			}}},
+			expected: Recipient{Type: KeyTypeVault, ID: "https://vault:8200/v1/sops/keys/tenant"},
+		},
+		{
+			key:      &keyservice.Key{KeyType: &keyservice.Key_KmsKey{KmsKey: &keyservice.KmsKey{Arn: "arn:aws:kms:eu-west-1:1:key/1"}}},
+			expected: Recipient{Type: KeyTypeAWSKMS, ID: "arn:aws:kms:eu-west-1:1:key/1"},
+		},
+		{
+			key: &keyservice.Key{KeyType: &keyservice.Key_AzureKeyvaultKey{AzureKeyvaultKey: &keyservice.AzureKeyVaultKey{
+				VaultUrl: "https://tenant.vault.azure.net",
+				Name:     "sops",
+				Version:  "1",
+			}}},
+			expected: Recipient{Type: KeyTypeAzureKV, ID: "https://tenant.vault.azure.net/keys/sops/1"},
+		},
+		{
+			key:      &keyservice.Key{KeyType: &keyservice.Key_GcpKmsKey{GcpKmsKey: &keyservice.GcpKmsKey{ResourceId: "projects/p/keyRings/r/cryptoKeys/k"}}},
+			expected: Recipient{Type: KeyTypeGCPKMS, ID: "projects/p/keyRings/r/cryptoKeys/k"},
+		},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expected, RecipientOf(tt.key))
+	}
+}
+
+func TestWithRecipients(t *testing.T) {
+	t.Parallel()
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	document, err := os.ReadFile("testdata/secret-age.yaml")
+	require.NoError(t, err)
+
+	d := NewSOPSDecryptor("")
+	require.NoError(t, d.AddAgeKey(key))
+
+	ctx, recipients := WithRecipients(context.Background())
+
+	// Decrypting twice with the same key records it once
+	for range 2 {
+		_, err = d.SopsDecryptWithFormat(ctx, document, logr.Discard(), formats.Yaml, formats.Yaml)
+		require.NoError(t, err)
+	}
+
+	list := recipients.List()
+	require.Len(t, list, 1)
+	require.Equal(t, KeyTypeAge, list[0].Type)
+	require.Contains(t, string(document), list[0].ID)
+
+	// Failed decryptions record no recipient
+	ctx, recipients = WithRecipients(context.Background())
+
+	_, err = NewSOPSDecryptor("").SopsDecryptWithFormat(ctx, document, logr.Discard(), formats.Yaml, formats.Yaml)
+	require.Error(t, err)
+	require.Empty(t, recipients.List())
+}
@@ -31,6 +31,8 @@ import (
 	"github.com/peak-scale/sops-operator/internal/decryptor/kustomize-controller/azkv"
 	intkeyservice "github.com/peak-scale/sops-operator/internal/decryptor/kustomize-controller/keyservice"
 	"github.com/peak-scale/sops-operator/internal/decryptor/kustomize-controller/pgp"
+	"github.com/peak-scale/sops-operator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -110,6 +112,9 @@ type SOPSDecryptor struct {
 	// decryptor.
 	keyServices      []keyservice.KeyServiceClient
 	localServiceOnce sync.Once
+
+	// observer is notified about decryptions and key service calls.
+	observer Observer
 }
 
 // NewDecryptor creates a new Decryptor for the given kustomization.
@@ -153,7 +158,7 @@ func (d *SOPSDecryptor) IsEncrypted(obj client.Object) (api.SopsImplementation,
 }
 
 // Read reads the input data, decrypts it, and returns the decrypted data.
-func (d *SOPSDecryptor) Decrypt(data *api.Metadata, secret *sopsv1alpha1.SopsSecretItem, log logr.Logger) error {
+func (d *SOPSDecryptor) Decrypt(ctx context.Context, data *api.Metadata, secret *sopsv1alpha1.SopsSecretItem, log logr.Logger) error {
 	// Loop over each secret item in the Spec.
 	// We need to restore the origin reference
 	entry := &sopsv1alpha1.SopsSecret{
@@ -171,7 +176,7 @@ func (d *SOPSDecryptor) Decrypt(data *api.Metadata, secret *sopsv1alpha1.SopsSec
 	outFormat := formats.Json
 
 	// Decrypt using SopsDecryptWithFormat.
-	decryptedBytes, err := d.SopsDecryptWithFormat(b, log, inFormat, outFormat)
+	decryptedBytes, err := d.SopsDecryptWithFormat(ctx, b, log, inFormat, outFormat)
 	if err != nil {
 		return fmt.Errorf("failed to decrypt secret field: %w", err)
 	}
@@ -183,6 +188,8 @@ func (d *SOPSDecryptor) Decrypt(data *api.Metadata, secret *sopsv1alpha1.SopsSec
 	// Rewrite Values
 	secret.Data = target.Spec.Secrets[0].Data
 	secret.StringData = target.Spec.Secrets[0].StringData
+	secret.DataFrom = target.Spec.Secrets[0].DataFrom
+	secret.EnvData = target.Spec.Secrets[0].EnvData
 
 	return nil
 }
@@ -232,6 +239,12 @@ func (d *SOPSDecryptor) SetGCPCredentials(config []byte) {
 }
 
 func (d *SOPSDecryptor) KeysFromSecret(ctx context.Context, c client.Client, secretName string, namespace string) (err error) {
+	ctx, span := tracing.Start(ctx, "SOPSDecryptor.KeysFromSecret",
+		attribute.String("secret.name", secretName),
+		attribute.String("secret.namespace", namespace),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// Retrieve Secret
 	var keySecret corev1.Secret
 	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretName}, &keySecret); err != nil {
@@ -242,16 +255,22 @@ func (d *SOPSDecryptor) KeysFromSecret(ctx context.Context, c client.Client, sec
 		return err
 	}
 
-	// Exract all keys from secret
-	for name, value := range keySecret.Data {
+	return d.KeysFromData(keySecret.Data, fmt.Sprintf("decryption Secret '%s'", secretName))
+}
+
+// KeysFromData imports the keys and credentials, using the same naming as the
+// data of a decryption Secret. Entries with other names are skipped. source
+// describes where the data is from in errors.
+func (d *SOPSDecryptor) KeysFromData(data map[string][]byte, source string) (err error) {
+	for name, value := range data {
 		switch filepath.Ext(name) {
 		case DecryptionPGPExt:
 			if err = d.AddGPGKey(value); err != nil {
-				return fmt.Errorf("failed to import data from %s decryption Secret '%s': %w", name, secretName, err)
+				return fmt.Errorf("failed to import data from %s %s: %w", name, source, err)
 			}
 		case DecryptionAgeExt:
 			if err = d.AddAgeKey(value); err != nil {
-				return fmt.Errorf("failed to import data from %s decryption Secret '%s': %w", name, secretName, err)
+				return fmt.Errorf("failed to import data from %s %s: %w", name, source, err)
 			}
 		case filepath.Ext(DecryptionVaultTokenFileName):
 			// Make sure we have the absolute name
@@ -260,14 +279,14 @@ func (d *SOPSDecryptor) KeysFromSecret(ctx context.Context, c client.Client, sec
 			}
 		case filepath.Ext(DecryptionAWSKmsFile):
 			if name == DecryptionAWSKmsFile {
-				if d.SetAWSCredentials(value); err != nil {
-					return fmt.Errorf("failed to import data from %s decryption Secret '%s': %w", name, secretName, err)
+				if err = d.SetAWSCredentials(value); err != nil {
+					return fmt.Errorf("failed to import data from %s %s: %w", name, source, err)
 				}
 			}
 		case filepath.Ext(DecryptionAzureAuthFile):
 			if name == DecryptionAzureAuthFile {
 				if err = d.SetAzureCredentials(value); err != nil {
-					return fmt.Errorf("failed to import data from %s decryption Secret '%s': %w", name, secretName, err)
+					return fmt.Errorf("failed to import data from %s %s: %w", name, source, err)
 				}
 			}
 		case filepath.Ext(DecryptionGCPCredsFile):
@@ -280,38 +299,78 @@ func (d *SOPSDecryptor) KeysFromSecret(ctx context.Context, c client.Client, sec
 	return nil
 }
 
+// SetCheckSopsMac enables the SOPS data integrity check using the MAC when
+// decrypting.
+func (d *SOPSDecryptor) SetCheckSopsMac(check bool) {
+	d.checkSopsMac = check
+}
+
 // SopsDecryptWithFormat attempts to load a SOPS encrypted file using the store
 // for the input format, gathers the data key for it from the key service,
 // and then decrypts the file data with the retrieved data key.
 // It returns the decrypted bytes in the provided output format, or an error.
-func (d *SOPSDecryptor) SopsDecryptWithFormat(data []byte, log logr.Logger, inputFormat, outputFormat formats.Format) (_ []byte, err error) {
+func (d *SOPSDecryptor) SopsDecryptWithFormat(
+	ctx context.Context,
+	data []byte,
+	log logr.Logger,
+	inputFormat, outputFormat formats.Format,
+) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "SOPSDecryptor.SopsDecryptWithFormat",
+		attribute.String("format.input", sopsFormatToString[inputFormat]),
+		attribute.String("format.output", sopsFormatToString[outputFormat]),
+	)
+
+	start := time.Now()
+	servers := d.keyServiceServer()
+	keyServices := observeKeyServices(traceKeyServices(ctx, servers), d.observer)
+	reason := ""
+
 	defer func() {
 		// It was discovered that malicious input and/or output instructions can
 		// make SOPS panic. Recover from this panic and return as an error.
 		if r := recover(); r != nil {
 			err = fmt.Errorf("failed to emit encrypted %s file as decrypted %s: %v",
 				sopsFormatToString[inputFormat], sopsFormatToString[outputFormat], r)
+			reason = FailureReasonEmit
+		}
+
+		if d.observer != nil {
+			d.observer.ObserveDecryption(decryptedKeyType(keyServices), time.Since(start), reason)
+		}
+
+		if reason != "" {
+			span.SetAttributes(attribute.String("failure.reason", reason))
 		}
+
+		tracing.End(span, err)
 	}()
 
 	store := common.StoreForFormat(inputFormat, config.NewStoresConfig())
 
 	tree, err := store.LoadEncryptedFile(data)
 	if err != nil {
+		reason = FailureReasonLoad
+
 		return nil, sopsUserErr(fmt.Sprintf("failed to load encrypted %s data", sopsFormatToString[inputFormat]), err)
 	}
 
 	if tree.Branches == nil {
+		reason = FailureReasonLoad
+
 		return nil, fmt.Errorf("tree.Branches is nil: invalid SOPS file structure")
 	}
 
-	keyService := d.keyServiceServer()
-	if keyService == nil {
+	// The wrapped key services are never nil, check the servers instead
+	if servers == nil {
+		reason = FailureReasonDataKey
+
 		return nil, fmt.Errorf("keyService is not initialized")
 	}
 
-	metadataKey, err := tree.Metadata.GetDataKeyWithKeyServices(d.keyServiceServer(), sops.DefaultDecryptionOrder)
+	metadataKey, err := tree.Metadata.GetDataKeyWithKeyServices(keyServices, sops.DefaultDecryptionOrder)
 	if err != nil {
+		reason = FailureReasonDataKey
+
 		return nil, sopsUserErr("cannot get sops data key", err)
 	}
 
@@ -319,6 +378,8 @@ func (d *SOPSDecryptor) SopsDecryptWithFormat(data []byte, log logr.Logger, inpu
 
 	mac, err := tree.Decrypt(metadataKey, cipher)
 	if err != nil {
+		reason = FailureReasonDecrypt
+
 		return nil, sopsUserErr("error decrypting sops tree", err)
 	}
 
@@ -333,6 +394,8 @@ func (d *SOPSDecryptor) SopsDecryptWithFormat(data []byte, log logr.Logger, inpu
 			tree.Metadata.LastModified.Format(time.RFC3339),
 		)
 		if err != nil {
+			reason = FailureReasonMAC
+
 			return nil, sopsUserErr("failed to verify sops data integrity", err)
 		}
 
@@ -342,6 +405,8 @@ func (d *SOPSDecryptor) SopsDecryptWithFormat(data []byte, log logr.Logger, inpu
 				originalMac = "no MAC"
 			}
 
+			reason = FailureReasonMAC
+
 			return nil, fmt.Errorf("failed to verify sops data integrity: expected mac '%s', got '%s'", originalMac, mac)
 		}
 	}
@@ -350,6 +415,8 @@ func (d *SOPSDecryptor) SopsDecryptWithFormat(data []byte, log logr.Logger, inpu
 
 	out, err := outputStore.EmitPlainFile(tree.Branches)
 	if err != nil {
+		reason = FailureReasonEmit
+
 		return nil, sopsUserErr(fmt.Sprintf("failed to emit encrypted %s file as decrypted %s",
 			sopsFormatToString[inputFormat], sopsFormatToString[outputFormat]), err)
 	}
@@ -0,0 +1,58 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"context"
+
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/peak-scale/sops-operator/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+)
+
+// Key service client tracing the calls and recording the recipients which
+// decrypted data keys. SOPS calls the key services without context, the
+// context of the decryption is used instead.
+type tracedKeyService struct {
+	keyservice.KeyServiceClient
+
+	ctx context.Context //nolint:containedctx
+}
+
+// Wraps the key services for a single decryption.
+func traceKeyServices(ctx context.Context, services []keyservice.KeyServiceClient) []keyservice.KeyServiceClient {
+	if services == nil {
+		return nil
+	}
+
+	traced := make([]keyservice.KeyServiceClient, 0, len(services))
+
+	for _, service := range services {
+		traced = append(traced, &tracedKeyService{
+			KeyServiceClient: service,
+			ctx:              ctx,
+		})
+	}
+
+	return traced
+}
+
+func (s *tracedKeyService) Decrypt(
+	_ context.Context,
+	req *keyservice.DecryptRequest,
+	opts ...grpc.CallOption,
+) (_ *keyservice.DecryptResponse, err error) {
+	ctx, span := tracing.Start(s.ctx, "keyservice.Server.Decrypt",
+		attribute.String("key.type", KeyTypeOf(req.GetKey())),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	resp, err := s.KeyServiceClient.Decrypt(ctx, req, opts...)
+	if err == nil {
+		recordRecipient(s.ctx, req.GetKey())
+	}
+
+	return resp, err
+}
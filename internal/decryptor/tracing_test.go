@@ -0,0 +1,96 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package decryptor
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/go-logr/logr"
+	"github.com/peak-scale/sops-operator/internal/tracing"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans of the trace, other tests may record spans concurrently.
+func spansOf(exporter *tracetest.InMemoryExporter, traceID trace.TraceID) map[string]tracetest.SpanStub {
+	spans := make(map[string]tracetest.SpanStub)
+
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID() == traceID {
+			spans[span.Name] = span
+		}
+	}
+
+	return spans
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	document, err := os.ReadFile("testdata/secret-age.yaml")
+	require.NoError(t, err)
+
+	t.Run("decryption", func(t *testing.T) {
+		d := NewSOPSDecryptor("")
+		require.NoError(t, d.AddAgeKey(key))
+
+		ctx, root := tracing.Start(context.Background(), "test")
+		_, err := d.SopsDecryptWithFormat(ctx, document, logr.Discard(), formats.Yaml, formats.Yaml)
+		require.NoError(t, err)
+		root.End()
+
+		spans := spansOf(exporter, root.SpanContext().TraceID())
+
+		decryption, ok := spans["SOPSDecryptor.SopsDecryptWithFormat"]
+		require.True(t, ok)
+		require.Equal(t, root.SpanContext().SpanID(), decryption.Parent.SpanID())
+		require.Equal(t, codes.Unset, decryption.Status.Code)
+		require.Equal(t, "YAML", spanAttribute(decryption, "format.input").AsString())
+
+		call, ok := spans["keyservice.Server.Decrypt"]
+		require.True(t, ok)
+		require.Equal(t, decryption.SpanContext.SpanID(), call.Parent.SpanID())
+		require.Equal(t, KeyTypeAge, spanAttribute(call, "key.type").AsString())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		d := NewSOPSDecryptor("")
+
+		ctx, root := tracing.Start(context.Background(), "test")
+		_, err := d.SopsDecryptWithFormat(ctx, document, logr.Discard(), formats.Yaml, formats.Yaml)
+		require.Error(t, err)
+		root.End()
+
+		spans := spansOf(exporter, root.SpanContext().TraceID())
+
+		decryption := spans["SOPSDecryptor.SopsDecryptWithFormat"]
+		require.Equal(t, codes.Error, decryption.Status.Code)
+		require.Equal(t, FailureReasonDataKey, spanAttribute(decryption, "failure.reason").AsString())
+
+		call := spans["keyservice.Server.Decrypt"]
+		require.Equal(t, codes.Error, call.Status.Code)
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"fmt"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Scheme contains the Kubernetes and SOPS operator types.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(Scheme))
+	utilruntime.Must(sopsv1alpha1.AddToScheme(Scheme))
+}
+
+// ClientOptions selects the cluster, the same way as kubectl.
+type ClientOptions struct {
+	// Kubeconfig is an explicit path to a kubeconfig file.
+	Kubeconfig string
+	// Context overrides the current kubeconfig context.
+	Context string
+	// Namespace overrides the namespace of the context.
+	Namespace string
+}
+
+// NewClient returns a client for the selected cluster and the namespace to
+// use for namespaced objects.
+func NewClient(opts ClientOptions) (client.Client, string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = opts.Kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: opts.Context}
+	overrides.Context.Namespace = opts.Namespace
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("load kubeconfig: %w", err)
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("load namespace: %w", err)
+	}
+
+	c, err := client.New(restConfig, client.Options{Scheme: Scheme})
+	if err != nil {
+		return nil, "", fmt.Errorf("create client: %w", err)
+	}
+
+	return c, namespace, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// ConvertOptions configures the conversion of a Secret.
+type ConvertOptions struct {
+	// Global converts into a GlobalSopsSecret instead of a SopsSecret.
+	Global bool
+	// Name of the SopsSecret, defaults to the name of the Secret.
+	Name string
+	// Namespace of the SopsSecret, or of the generated Secret for a
+	// GlobalSopsSecret. Defaults to the namespace of the Secret.
+	Namespace string
+}
+
+// Annotations and labels managed by Kubernetes tooling, which are not copied.
+var ignoredMetadataPrefixes = []string{
+	"kubectl.kubernetes.io/",
+	"meta.helm.sh/",
+}
+
+// Convert a Secret into a SopsSecret or GlobalSopsSecret, which generates the
+// same Secret once decrypted. Data is kept base64 encoded as data.
+func Convert(secret *corev1.Secret, opts ConvertOptions) (client.Object, error) {
+	if secret.Name == "" {
+		return nil, fmt.Errorf("secret has no name")
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = secret.Namespace
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = secret.Name
+	}
+
+	item := sopsv1alpha1.SopsSecretItem{
+		Name:        secret.Name,
+		Labels:      copyMetadata(secret.Labels),
+		Annotations: copyMetadata(secret.Annotations),
+		Type:        secret.Type,
+	}
+
+	if len(secret.Data) > 0 {
+		item.Data = make(map[string]string, len(secret.Data))
+		for key, value := range secret.Data {
+			item.Data[key] = base64.StdEncoding.EncodeToString(value)
+		}
+	}
+
+	if len(secret.StringData) > 0 {
+		item.StringData = maps.Clone(secret.StringData)
+	}
+
+	if opts.Global {
+		if namespace == "" {
+			return nil, fmt.Errorf("namespace of secret %q is required for a GlobalSopsSecret", secret.Name)
+		}
+
+		return &sopsv1alpha1.GlobalSopsSecret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: sopsv1alpha1.GroupVersion.String(),
+				Kind:       "GlobalSopsSecret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: sopsv1alpha1.GlobalSopsSecretSpec{
+				Secrets: []*sopsv1alpha1.GlobalSopsSecretItem{
+					{SopsSecretItem: item, Namespace: namespace},
+				},
+			},
+		}, nil
+	}
+
+	return &sopsv1alpha1.SopsSecret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sopsv1alpha1.GroupVersion.String(),
+			Kind:       "SopsSecret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: sopsv1alpha1.SopsSecretSpec{
+			Secrets: []*sopsv1alpha1.SopsSecretItem{&item},
+		},
+	}, nil
+}
+
+// Manifest renders the object as YAML, without status and SOPS metadata, so
+// it can be encrypted.
+func Manifest(obj client.Object) ([]byte, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest map[string]any
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+
+	delete(manifest, "status")
+	delete(manifest, "sops")
+
+	return yaml.Marshal(manifest)
+}
+
+func copyMetadata(values map[string]string) map[string]string {
+	copied := make(map[string]string, len(values))
+
+	for key, value := range values {
+		if !ignoredMetadata(key) {
+			copied[key] = value
+		}
+	}
+
+	if len(copied) == 0 {
+		return nil
+	}
+
+	return copied
+}
+
+func ignoredMetadata(key string) bool {
+	for _, prefix := range ignoredMetadataPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
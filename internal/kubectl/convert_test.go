@@ -0,0 +1,139 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const testAgeRecipient = "age1p0wmaw5vk8f00753t3frs4rev0du4vqdkz7sx53ml98lrcsrnuqqwwp4tl"
+
+func testSecret() *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "database",
+			Namespace: "app",
+			Labels:    map[string]string{"app": "database"},
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"team": "platform",
+			},
+			UID:             "uid",
+			ResourceVersion: "1",
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("secret"),
+		},
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	obj, err := Convert(testSecret(), ConvertOptions{})
+	require.NoError(t, err)
+
+	sopsSecret, ok := obj.(*sopsv1alpha1.SopsSecret)
+	require.True(t, ok)
+	require.Equal(t, "SopsSecret", sopsSecret.Kind)
+	require.Equal(t, "database", sopsSecret.Name)
+	require.Equal(t, "app", sopsSecret.Namespace)
+	require.Len(t, sopsSecret.Spec.Secrets, 1)
+
+	item := sopsSecret.Spec.Secrets[0]
+	require.Equal(t, "database", item.Name)
+	require.Equal(t, corev1.SecretTypeBasicAuth, item.Type)
+	require.Equal(t, map[string]string{"app": "database"}, item.Labels)
+	require.Equal(t, map[string]string{"team": "platform"}, item.Annotations)
+	require.Equal(t, map[string]string{"username": "YWRtaW4=", "password": "c2VjcmV0"}, item.Data)
+
+	obj, err = Convert(testSecret(), ConvertOptions{Global: true, Name: "shared-database", Namespace: "other"})
+	require.NoError(t, err)
+
+	global, ok := obj.(*sopsv1alpha1.GlobalSopsSecret)
+	require.True(t, ok)
+	require.Equal(t, "shared-database", global.Name)
+	require.Empty(t, global.Namespace)
+	require.Equal(t, "other", global.Spec.Secrets[0].Namespace)
+	require.Equal(t, "database", global.Spec.Secrets[0].Name)
+
+	secret := testSecret()
+	secret.Namespace = ""
+
+	_, err = Convert(secret, ConvertOptions{Global: true})
+	require.Error(t, err)
+}
+
+func TestManifest(t *testing.T) {
+	t.Parallel()
+
+	obj, err := Convert(testSecret(), ConvertOptions{Global: true})
+	require.NoError(t, err)
+
+	manifest, err := Manifest(obj)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, yaml.Unmarshal(manifest, &decoded))
+	require.Equal(t, "GlobalSopsSecret", decoded["kind"])
+	require.NotContains(t, decoded, "sops")
+	require.NotContains(t, decoded, "status")
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".sops.yaml"), []byte(`creation_rules:
+  - path_regex: \.yaml$
+    encrypted_regex: ^password$
+    age: `+testAgeRecipient+`
+`), 0o644))
+
+	obj, err := Convert(testSecret(), ConvertOptions{})
+	require.NoError(t, err)
+
+	manifest, err := Manifest(obj)
+	require.NoError(t, err)
+
+	encrypted, err := Encrypt(manifest, "", filepath.Join(dir, "database.yaml"))
+	require.NoError(t, err)
+
+	var sopsSecret sopsv1alpha1.SopsSecret
+	require.NoError(t, yaml.Unmarshal(encrypted, &sopsSecret))
+	require.Equal(t, "database", sopsSecret.Name)
+	require.Equal(t, "database", sopsSecret.Spec.Secrets[0].Name)
+	require.Equal(t, "database", sopsSecret.Spec.Secrets[0].Labels["app"])
+	require.NotNil(t, sopsSecret.Sops)
+	require.Equal(t, EncryptedRegex, sopsSecret.Sops.EncryptedRegex)
+	require.Contains(t, sopsSecret.Spec.Secrets[0].Data["password"], "ENC[")
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	dec := decryptor.NewSOPSDecryptor("")
+	require.NoError(t, dec.AddAgeKey(key))
+
+	item := sopsSecret.Spec.Secrets[0]
+	require.NoError(t, dec.Decrypt(context.Background(), sopsSecret.Sops, item, logr.Discard()))
+	require.Equal(t, "c2VjcmV0", item.Data["password"])
+
+	_, err = Encrypt(manifest, filepath.Join(dir, ".sops.yaml"), filepath.Join(dir, "database.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	"github.com/getsops/sops/v3/cmd/sops/common"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/config"
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/getsops/sops/v3/version"
+)
+
+// EncryptedRegex limits encryption to the values of SopsSecret items, the
+// rest of the manifest must remain readable by the API server.
+const EncryptedRegex = "^(data|stringData)$"
+
+// Encrypt a SopsSecret or GlobalSopsSecret manifest with the keys of the
+// .sops.yaml creation rule matching path. When configPath is empty, the
+// .sops.yaml is discovered from path. The encryption settings of the creation
+// rule are replaced with EncryptedRegex.
+func Encrypt(manifest []byte, configPath, path string) ([]byte, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if configPath == "" {
+		result, err := config.LookupConfigFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("no .sops.yaml found for %q: %w", path, err)
+		}
+
+		configPath = result.Path
+	}
+
+	rule, err := config.LoadCreationRuleForFile(configPath, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("load creation rule for %q: %w", path, err)
+	}
+
+	if rule == nil || len(rule.KeyGroups) == 0 {
+		return nil, fmt.Errorf("no creation rule with keys matches %q in %q", path, configPath)
+	}
+
+	store := common.StoreForFormat(formats.Yaml, config.NewStoresConfig())
+
+	branches, err := store.LoadPlainFile(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("load manifest: %w", err)
+	}
+
+	if len(branches) != 1 {
+		return nil, fmt.Errorf("expected a single manifest, got %d documents", len(branches))
+	}
+
+	tree := sops.Tree{
+		Branches: branches,
+		Metadata: sops.Metadata{
+			KeyGroups:       rule.KeyGroups,
+			ShamirThreshold: rule.ShamirThreshold,
+			EncryptedRegex:  EncryptedRegex,
+			Version:         version.Version,
+		},
+		FilePath: path,
+	}
+
+	dataKey, errs := tree.GenerateDataKeyWithKeyServices([]keyservice.KeyServiceClient{keyservice.NewLocalClient()})
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("generate data key: %v", errs)
+	}
+
+	if err := common.EncryptTree(common.EncryptTreeOpts{
+		DataKey: dataKey,
+		Tree:    &tree,
+		Cipher:  aes.NewCipher(),
+	}); err != nil {
+		return nil, fmt.Errorf("encrypt manifest: %w", err)
+	}
+
+	return store.EmitEncryptedFile(tree)
+}
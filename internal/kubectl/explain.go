@@ -0,0 +1,453 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"filippo.io/age"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	sopsage "github.com/peak-scale/sops-operator/internal/decryptor/kustomize-controller/age"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Recipient states.
+const (
+	// RecipientAvailable is a recipient, whose key is loaded from a key Secret.
+	RecipientAvailable = "Available"
+	// RecipientMissing is a recipient, whose key is not loaded from any key Secret.
+	RecipientMissing = "Missing"
+	// RecipientUnverified is a recipient of a key service, whose access can
+	// not be verified client-side.
+	RecipientUnverified = "Unverified"
+)
+
+// Recipient types, as in the SOPS metadata.
+const (
+	recipientAge      = "age"
+	recipientPGP      = "pgp"
+	recipientKMS      = "kms"
+	recipientGCPKMS   = "gcp_kms"
+	recipientAzureKV  = "azure_kv"
+	recipientVault    = "hc_vault"
+	recipientHuaweiKM = "hckms"
+)
+
+// Credential files of key Secrets by recipient type.
+var credentialFiles = map[string]string{
+	recipientKMS:     decryptor.DecryptionAWSKmsFile,
+	recipientGCPKMS:  decryptor.DecryptionGCPCredsFile,
+	recipientAzureKV: decryptor.DecryptionAzureAuthFile,
+	recipientVault:   decryptor.DecryptionVaultTokenFileName,
+}
+
+// Explanation describes how the controller decrypts a SopsSecret or
+// GlobalSopsSecret.
+type Explanation struct {
+	Kind      string
+	Name      string
+	Namespace string
+	// Ready condition of the object, nil when not reconciled yet.
+	Ready *metav1.Condition
+	// Encrypted is false, when the object has no SOPS metadata.
+	Encrypted bool
+	// Providers selecting the object.
+	Providers []ProviderExplanation
+	// Recipients in the SOPS metadata of the object.
+	Recipients []RecipientExplanation
+}
+
+// ProviderExplanation describes a provider selecting the object.
+type ProviderExplanation struct {
+	Name  string
+	Ready metav1.ConditionStatus
+	// KeySecrets selected by the provider.
+	KeySecrets []KeySecretExplanation
+}
+
+// KeySecretExplanation describes a key Secret of a provider.
+type KeySecretExplanation struct {
+	Name      string
+	Namespace string
+	// Loaded is true, when the provider loaded the keys of the Secret. Only
+	// loaded Secrets are used for decryption.
+	Loaded bool
+	// Message of the provider status for the Secret.
+	Message string
+	// Keys contains the age recipients and PGP fingerprints of the keys and
+	// the key service credentials in the Secret.
+	Keys []string
+
+	ageRecipients   []string
+	pgpFingerprints []string
+	credentials     []string
+}
+
+// RecipientExplanation describes a recipient of the object.
+type RecipientExplanation struct {
+	Type      string
+	Recipient string
+	Status    string
+	// Source of the key or credentials.
+	Source string
+}
+
+// Explain replays the provider selection and key loading of the controller for
+// a SopsSecret or GlobalSopsSecret, which does not need to exist in the
+// cluster.
+func Explain(ctx context.Context, c client.Client, obj api.SopsImplementation) (*Explanation, error) {
+	explanation := &Explanation{
+		Kind:      obj.GetObjectKind().GroupVersionKind().Kind,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Ready:     readyCondition(obj),
+		Encrypted: obj.GetSopsMetadata() != nil,
+	}
+
+	providers, err := MatchingProviders(ctx, c, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := keySecretCandidates(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, provider := range providers {
+		explained := ProviderExplanation{
+			Name:  provider.Name,
+			Ready: metav1.ConditionUnknown,
+		}
+
+		if condition := provider.Status.Conditions.GetConditionByType(capmeta.ReadyCondition); condition != nil {
+			explained.Ready = condition.Status
+		}
+
+		selected, err := provider.selectKeySecrets(ctx, c, candidates)
+		if err != nil {
+			return nil, fmt.Errorf("select key secrets of provider %q: %w", provider.Name, err)
+		}
+
+		// Referenced key Secrets are loaded with the referenced keys only, like
+		// in the controller
+		for _, ref := range provider.keySecretRefs {
+			secret, err := ref.Fetch(ctx, c)
+			if err != nil {
+				explained.KeySecrets = append(explained.KeySecrets, KeySecretExplanation{
+					Name:      ref.Name,
+					Namespace: ref.Namespace,
+					Message:   err.Error(),
+				})
+
+				continue
+			}
+
+			selected[string(secret.UID)] = secret
+		}
+
+		for _, secret := range selected {
+			explained.KeySecrets = append(explained.KeySecrets, explainKeySecret(&provider, secret))
+		}
+
+		slices.SortFunc(explained.KeySecrets, func(a, b KeySecretExplanation) int {
+			return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+		})
+
+		explanation.Providers = append(explanation.Providers, explained)
+	}
+
+	if metadata := obj.GetSopsMetadata(); metadata != nil {
+		explanation.Recipients = explainRecipients(metadata, explanation.Providers)
+	}
+
+	return explanation, nil
+}
+
+// Key Secrets considered by providers, labeled with meta.KeySecretLabel.
+func keySecretCandidates(ctx context.Context, c client.Client) ([]*corev1.Secret, error) {
+	secretList := &corev1.SecretList{}
+	if err := c.List(ctx, secretList, client.MatchingLabelsSelector{Selector: meta.KeySecretSelector()}); err != nil {
+		return nil, fmt.Errorf("list key secrets: %w", err)
+	}
+
+	candidates := make([]*corev1.Secret, 0, len(secretList.Items))
+	for i := range secretList.Items {
+		candidates = append(candidates, &secretList.Items[i])
+	}
+
+	return candidates, nil
+}
+
+func explainKeySecret(provider *Provider, secret *corev1.Secret) KeySecretExplanation {
+	explained := KeySecretExplanation{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Message:   "not loaded by provider yet",
+	}
+
+	for _, status := range provider.Status.Providers {
+		if status.UID == secret.UID {
+			explained.Loaded = status.Status == metav1.ConditionTrue
+			explained.Message = status.Message
+		}
+	}
+
+	for name, value := range secret.Data {
+		switch filepath.Ext(name) {
+		case decryptor.DecryptionAgeExt:
+			explained.ageRecipients = append(explained.ageRecipients, ageRecipients(value)...)
+		case decryptor.DecryptionPGPExt:
+			explained.pgpFingerprints = append(explained.pgpFingerprints, pgpFingerprints(value)...)
+		}
+
+		for recipientType, file := range credentialFiles {
+			if name == file {
+				explained.credentials = append(explained.credentials, recipientType)
+			}
+		}
+	}
+
+	slices.Sort(explained.ageRecipients)
+	slices.Sort(explained.pgpFingerprints)
+	slices.Sort(explained.credentials)
+
+	explained.Keys = slices.Concat(explained.ageRecipients, explained.pgpFingerprints)
+	for _, credential := range explained.credentials {
+		explained.Keys = append(explained.Keys, credential+" credentials")
+	}
+
+	return explained
+}
+
+// Recipients of age identities, identities without recipient are skipped.
+func ageRecipients(value []byte) []string {
+	var identities sopsage.ParsedIdentities
+	if err := identities.Import(string(value)); err != nil {
+		return nil
+	}
+
+	recipients := make([]string, 0, len(identities))
+
+	for _, identity := range identities {
+		if x25519, ok := identity.(*age.X25519Identity); ok {
+			recipients = append(recipients, x25519.Recipient().String())
+		}
+	}
+
+	return recipients
+}
+
+// Fingerprints of the primary keys and subkeys of an armored key ring.
+func pgpFingerprints(value []byte) []string {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(value))
+	if err != nil {
+		return nil
+	}
+
+	fingerprints := make([]string, 0)
+
+	for _, entity := range entities {
+		fingerprints = append(fingerprints, strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint)))
+
+		for _, subkey := range entity.Subkeys {
+			fingerprints = append(fingerprints, strings.ToUpper(hex.EncodeToString(subkey.PublicKey.Fingerprint)))
+		}
+	}
+
+	return fingerprints
+}
+
+func explainRecipients(metadata *api.Metadata, providers []ProviderExplanation) []RecipientExplanation {
+	groups := append([]api.Keygroup{{
+		Pgpkeys:           metadata.Pgpkeys,
+		Kmskeys:           metadata.Kmskeys,
+		GcpKmskeys:        metadata.GcpKmskeys,
+		Hckmskeys:         metadata.Hckmskeys,
+		AzureKeyVaultkeys: metadata.AzureKeyVaultkeys,
+		Vaultkeys:         metadata.Vaultkeys,
+		Agekeys:           metadata.Agekeys,
+	}}, metadata.KeyGroups...)
+
+	recipients := make([]RecipientExplanation, 0)
+
+	for _, group := range groups {
+		for _, key := range group.Agekeys {
+			recipients = append(recipients, RecipientExplanation{Type: recipientAge, Recipient: key.Recipient})
+		}
+
+		for _, key := range group.Pgpkeys {
+			recipients = append(recipients, RecipientExplanation{Type: recipientPGP, Recipient: strings.ToUpper(key.Fingerprint)})
+		}
+
+		for _, key := range group.Kmskeys {
+			recipient := key.Arn
+			if key.Role != "" {
+				recipient += "+" + key.Role
+			}
+
+			recipients = append(recipients, RecipientExplanation{Type: recipientKMS, Recipient: recipient})
+		}
+
+		for _, key := range group.GcpKmskeys {
+			recipients = append(recipients, RecipientExplanation{Type: recipientGCPKMS, Recipient: key.ResourceID})
+		}
+
+		for _, key := range group.AzureKeyVaultkeys {
+			recipients = append(recipients, RecipientExplanation{
+				Type:      recipientAzureKV,
+				Recipient: fmt.Sprintf("%s/keys/%s/%s", key.VaultURL, key.Name, key.Version),
+			})
+		}
+
+		for _, key := range group.Vaultkeys {
+			recipients = append(recipients, RecipientExplanation{
+				Type:      recipientVault,
+				Recipient: fmt.Sprintf("%s/v1/%s/keys/%s", key.VaultAddress, key.EnginePath, key.KeyName),
+			})
+		}
+
+		for _, key := range group.Hckmskeys {
+			recipients = append(recipients, RecipientExplanation{Type: recipientHuaweiKM, Recipient: key.KeyID})
+		}
+	}
+
+	for i := range recipients {
+		recipients[i].Status, recipients[i].Source = recipientSource(recipients[i], providers)
+	}
+
+	return recipients
+}
+
+// Status and source of a recipient, from the loaded key Secrets.
+func recipientSource(recipient RecipientExplanation, providers []ProviderExplanation) (string, string) {
+	for _, provider := range providers {
+		for _, secret := range provider.KeySecrets {
+			if !secret.Loaded {
+				continue
+			}
+
+			source := fmt.Sprintf("%s: %s/%s", provider.Name, secret.Namespace, secret.Name)
+
+			switch recipient.Type {
+			case recipientAge:
+				if slices.Contains(secret.ageRecipients, recipient.Recipient) {
+					return RecipientAvailable, source
+				}
+			case recipientPGP:
+				for _, fingerprint := range secret.pgpFingerprints {
+					if strings.HasSuffix(fingerprint, recipient.Recipient) {
+						return RecipientAvailable, source
+					}
+				}
+			default:
+				if slices.Contains(secret.credentials, recipient.Type) {
+					return RecipientUnverified, "credentials from " + source
+				}
+			}
+		}
+	}
+
+	switch recipient.Type {
+	case recipientAge, recipientPGP:
+		return RecipientMissing, "no loaded key Secret contains the key"
+	default:
+		return RecipientUnverified, "no credentials in loaded key Secrets, requires ambient credentials of the controller"
+	}
+}
+
+func readyCondition(obj client.Object) *metav1.Condition {
+	var conditions capmeta.ConditionList
+
+	switch o := obj.(type) {
+	case *sopsv1alpha1.SopsSecret:
+		conditions = o.Status.Conditions
+	case *sopsv1alpha1.GlobalSopsSecret:
+		conditions = o.Status.Conditions
+	}
+
+	condition := conditions.GetConditionByType(capmeta.ReadyCondition)
+	if condition == nil {
+		return nil
+	}
+
+	return (*metav1.Condition)(condition)
+}
+
+// Write the explanation as text.
+func (e *Explanation) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	name := e.Name
+	if e.Namespace != "" {
+		name = e.Namespace + "/" + name
+	}
+
+	fmt.Fprintf(tw, "%s %s\n", e.Kind, name)
+
+	if e.Ready != nil {
+		fmt.Fprintf(tw, "Ready:\t%s (%s)\n", e.Ready.Status, e.Ready.Message)
+	} else {
+		fmt.Fprintf(tw, "Ready:\tnot reconciled\n")
+	}
+
+	if !e.Encrypted {
+		fmt.Fprintf(tw, "Encrypted:\tfalse, the object has no sops metadata\n")
+	}
+
+	fmt.Fprintf(tw, "\nProviders:\n")
+
+	if len(e.Providers) == 0 {
+		fmt.Fprintf(tw, "  no provider selects the object, check the selectors of the providers and the labels of the object and its namespace\n")
+	}
+
+	for _, provider := range e.Providers {
+		fmt.Fprintf(tw, "  %s\tReady: %s\n", provider.Name, provider.Ready)
+
+		if len(provider.KeySecrets) == 0 {
+			fmt.Fprintf(tw, "    no key Secrets selected or referenced, selected key Secrets require the %s label\n", meta.KeySecretLabel)
+		}
+
+		for _, secret := range provider.KeySecrets {
+			state := "loaded"
+			if !secret.Loaded {
+				state = "not loaded: " + secret.Message
+			}
+
+			keys := strings.Join(secret.Keys, ", ")
+			if keys == "" {
+				keys = "no keys"
+			}
+
+			fmt.Fprintf(tw, "    %s/%s\t%s\t%s\n", secret.Namespace, secret.Name, state, keys)
+		}
+	}
+
+	fmt.Fprintf(tw, "\nRecipients:\n")
+
+	if len(e.Recipients) == 0 {
+		fmt.Fprintf(tw, "  none\n")
+	}
+
+	for _, recipient := range e.Recipients {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", recipient.Type, recipient.Recipient, recipient.Status, recipient.Source)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,262 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"filippo.io/age"
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "keys",
+			Namespace: "sops-system",
+			UID:       "key-uid",
+			Labels:    map[string]string{meta.KeySecretLabel: "true", "provider": "platform"},
+		},
+		Data: map[string][]byte{"age.agekey": key},
+	}
+
+	unselected := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-keys",
+			Namespace: "sops-system",
+			Labels:    map[string]string{meta.KeySecretLabel: "true"},
+		},
+	}
+
+	provider := &sopsv1alpha1.SopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+		Spec: sopsv1alpha1.SopsProviderSpec{
+			SOPSSelectors: []*api.NamespacedSelector{{}},
+			ProviderSecrets: []*api.NamespacedSelector{{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"provider": "platform"}},
+			}},
+		},
+		Status: sopsv1alpha1.SopsProviderStatus{
+			Conditions: capmeta.ConditionList{{Type: capmeta.ReadyCondition, Status: metav1.ConditionTrue}},
+			Providers: []*sopsv1alpha1.SopsProviderItemStatus{{
+				Origin:    api.Origin{Name: "keys", Namespace: "sops-system", UID: "key-uid"},
+				Condition: metav1.Condition{Status: metav1.ConditionTrue},
+			}},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(keySecret, unselected, provider).Build()
+
+	secret := &sopsv1alpha1.SopsSecret{
+		TypeMeta:   metav1.TypeMeta{Kind: "SopsSecret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "app"},
+		Status: sopsv1alpha1.SopsSecretStatus{
+			Conditions: capmeta.ConditionList{{
+				Type:    capmeta.ReadyCondition,
+				Status:  metav1.ConditionFalse,
+				Message: "Secret reconciliation failed",
+			}},
+		},
+		Sops: &api.Metadata{
+			Agekeys: []api.Agekey{
+				{Recipient: testAgeRecipient},
+				{Recipient: "age1s7t2vk2crlxaumgm7cacs568xwutkjs535pla69kt6w006t7wgzqhkfwvp"},
+			},
+			Kmskeys: []api.Kmskey{{Arn: "arn:aws:kms:eu-central-1:123456789012:key/key"}},
+		},
+	}
+
+	explanation, err := Explain(context.Background(), c, secret)
+	require.NoError(t, err)
+
+	require.True(t, explanation.Encrypted)
+	require.Equal(t, metav1.ConditionFalse, explanation.Ready.Status)
+	require.Len(t, explanation.Providers, 1)
+	require.Equal(t, metav1.ConditionTrue, explanation.Providers[0].Ready)
+	require.Len(t, explanation.Providers[0].KeySecrets, 1)
+	require.True(t, explanation.Providers[0].KeySecrets[0].Loaded)
+	require.Equal(t, []string{testAgeRecipient}, explanation.Providers[0].KeySecrets[0].Keys)
+
+	require.Len(t, explanation.Recipients, 3)
+	require.Equal(t, RecipientAvailable, explanation.Recipients[0].Status)
+	require.Equal(t, "platform: sops-system/keys", explanation.Recipients[0].Source)
+	require.Equal(t, RecipientMissing, explanation.Recipients[1].Status)
+	require.Equal(t, "kms", explanation.Recipients[2].Type)
+	require.Equal(t, RecipientUnverified, explanation.Recipients[2].Status)
+
+	var out bytes.Buffer
+	require.NoError(t, explanation.Write(&out))
+	require.Contains(t, out.String(), "SopsSecret app/database\n")
+	require.Contains(t, out.String(), "sops-system/keys  loaded  "+testAgeRecipient)
+}
+
+func TestExplainNamespacedProvider(t *testing.T) {
+	t.Parallel()
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	tenant := map[string]string{capmeta.NewTenantLabel: "solar"}
+
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "keys",
+			Namespace: "solar-keys",
+			UID:       "key-uid",
+			Labels:    map[string]string{meta.KeySecretLabel: "true", "provider": "solar"},
+		},
+		Data: map[string][]byte{"age.agekey": key},
+	}
+
+	provider := &sopsv1alpha1.NamespacedSopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "solar", Namespace: "solar-keys"},
+		Spec: sopsv1alpha1.NamespacedSopsProviderSpec{
+			SecretSelectors:  []*metav1.LabelSelector{{}},
+			KeySelectors:     []*metav1.LabelSelector{{MatchLabels: map[string]string{"provider": "solar"}}},
+			TenantNamespaces: true,
+		},
+		Status: sopsv1alpha1.SopsProviderStatus{
+			Conditions: capmeta.ConditionList{{Type: capmeta.ReadyCondition, Status: metav1.ConditionTrue}},
+			Providers: []*sopsv1alpha1.SopsProviderItemStatus{{
+				Origin:    api.Origin{Name: "keys", Namespace: "solar-keys", UID: "key-uid"},
+				Condition: metav1.Condition{Status: metav1.ConditionTrue},
+			}},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "solar-keys", Labels: tenant}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "solar-prod", Labels: tenant}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "wind-prod"}},
+		keySecret,
+		provider,
+	).Build()
+
+	secret := &sopsv1alpha1.SopsSecret{
+		TypeMeta:   metav1.TypeMeta{Kind: "SopsSecret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "solar-prod"},
+		Sops: &api.Metadata{
+			Agekeys: []api.Agekey{{Recipient: testAgeRecipient}},
+		},
+	}
+
+	// Selected in the other namespaces of the tenant
+	explanation, err := Explain(context.Background(), c, secret)
+	require.NoError(t, err)
+	require.Len(t, explanation.Providers, 1)
+	require.Equal(t, "solar-keys/solar", explanation.Providers[0].Name)
+	require.Len(t, explanation.Providers[0].KeySecrets, 1)
+	require.True(t, explanation.Providers[0].KeySecrets[0].Loaded)
+	require.Equal(t, RecipientAvailable, explanation.Recipients[0].Status)
+	require.Equal(t, "solar-keys/solar: solar-keys/keys", explanation.Recipients[0].Source)
+
+	// Not selected outside of the tenant
+	secret.Namespace = "wind-prod"
+
+	explanation, err = Explain(context.Background(), c, secret)
+	require.NoError(t, err)
+	require.Empty(t, explanation.Providers)
+	require.Equal(t, RecipientMissing, explanation.Recipients[0].Status)
+}
+
+func TestExplainKeySecretRefs(t *testing.T) {
+	t.Parallel()
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	// Referenced key Secrets do not need the key Secret label
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "sops-system", UID: "key-uid"},
+		Data: map[string][]byte{
+			"ci.agekey":    key,
+			"other.agekey": []byte(other.String()),
+		},
+	}
+
+	provider := &sopsv1alpha1.SopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+		Spec: sopsv1alpha1.SopsProviderSpec{
+			SOPSSelectors: []*api.NamespacedSelector{{}},
+			KeySecretRefs: []*api.KeySecretReference{
+				{Namespace: "sops-system", Name: "keys", Keys: []string{"ci.agekey"}},
+				{Namespace: "sops-system", Name: "missing"},
+			},
+		},
+		Status: sopsv1alpha1.SopsProviderStatus{
+			Providers: []*sopsv1alpha1.SopsProviderItemStatus{{
+				Origin:    api.Origin{Name: "keys", Namespace: "sops-system", UID: "key-uid"},
+				Condition: metav1.Condition{Status: metav1.ConditionTrue},
+			}},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(keySecret, provider).Build()
+
+	explanation, err := Explain(context.Background(), c, &sopsv1alpha1.SopsSecret{
+		TypeMeta:   metav1.TypeMeta{Kind: "SopsSecret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "app"},
+		Sops: &api.Metadata{
+			Agekeys: []api.Agekey{
+				{Recipient: testAgeRecipient},
+				{Recipient: other.Recipient().String()},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, explanation.Providers, 1)
+	require.Len(t, explanation.Providers[0].KeySecrets, 2)
+
+	// Only the referenced keys are loaded
+	require.Equal(t, "keys", explanation.Providers[0].KeySecrets[0].Name)
+	require.True(t, explanation.Providers[0].KeySecrets[0].Loaded)
+	require.Equal(t, []string{testAgeRecipient}, explanation.Providers[0].KeySecrets[0].Keys)
+
+	require.Equal(t, "missing", explanation.Providers[0].KeySecrets[1].Name)
+	require.False(t, explanation.Providers[0].KeySecrets[1].Loaded)
+	require.Contains(t, explanation.Providers[0].KeySecrets[1].Message, "failed to get key secret sops-system/missing")
+
+	require.Equal(t, RecipientAvailable, explanation.Recipients[0].Status)
+	require.Equal(t, RecipientMissing, explanation.Recipients[1].Status)
+}
+
+func TestExplainWithoutProviders(t *testing.T) {
+	t.Parallel()
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).Build()
+
+	explanation, err := Explain(context.Background(), c, &sopsv1alpha1.GlobalSopsSecret{
+		TypeMeta:   metav1.TypeMeta{Kind: "GlobalSopsSecret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+	})
+	require.NoError(t, err)
+	require.False(t, explanation.Encrypted)
+	require.Nil(t, explanation.Ready)
+	require.Empty(t, explanation.Providers)
+
+	var out bytes.Buffer
+	require.NoError(t, explanation.Write(&out))
+	require.Contains(t, out.String(), "no provider selects the object")
+	require.Contains(t, out.String(), "the object has no sops metadata")
+}
@@ -0,0 +1,566 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/go-logr/logr"
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Group of the SopsSecrets of the isindir sops-secrets-operator.
+	isindirGroup = "isindir.github.com"
+	// Group of the Flux Kustomizations.
+	fluxKustomizeGroup = "kustomize.toolkit.fluxcd.io"
+	// Decryption provider of Flux Kustomizations using SOPS.
+	fluxSopsProvider = "sops"
+)
+
+// MigrateOptions configures the migration of manifests.
+type MigrateOptions struct {
+	// Decryptor with the local keys, used to decrypt the migrated manifests.
+	// The keys of Flux decryption Secrets in the sources are imported as well,
+	// so the decryptor must not have been used before.
+	Decryptor *decryptor.SOPSDecryptor
+	// ConfigPath of the .sops.yaml, discovered from the path of each migrated
+	// object when empty.
+	ConfigPath string
+}
+
+// MigrationSource is a manifest with one or more documents to migrate.
+type MigrationSource struct {
+	// Path matched against the .sops.yaml creation rules when re-encrypting the
+	// objects of the source. Defaults to <name>.yaml for each object.
+	Path string
+	// Content of the manifest, Lists are expanded.
+	Content []byte
+}
+
+// Migration is the result of a migration.
+type Migration struct {
+	// Manifests of the SopsProviders and encrypted SopsSecrets.
+	Manifests [][]byte
+	// Notes about objects which were skipped or require manual steps.
+	Notes []string
+}
+
+// Document of a source.
+type migrationObject struct {
+	path   string
+	object map[string]any
+	gvk    schema.GroupVersionKind
+}
+
+func (o migrationObject) name() string {
+	name := metadataString(o.object, "name")
+	if namespace := metadataString(o.object, "namespace"); namespace != "" {
+		name = namespace + "/" + name
+	}
+
+	return fmt.Sprintf("%s %s", o.gvk.Kind, name)
+}
+
+// Manifest of a SopsSecret of the isindir sops-secrets-operator.
+type isindirSopsSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec struct {
+		SecretTemplates []struct {
+			Name        string            `json:"name"`
+			Labels      map[string]string `json:"labels,omitempty"`
+			Annotations map[string]string `json:"annotations,omitempty"`
+			Type        corev1.SecretType `json:"type,omitempty"`
+			Data        map[string]string `json:"data,omitempty"`
+			StringData  map[string]string `json:"stringData,omitempty"`
+		} `json:"secretTemplates"`
+		Suspend bool `json:"suspend,omitempty"`
+	} `json:"spec"`
+}
+
+// Manifest of a Flux Kustomization, only the decryption settings are used.
+type fluxKustomization struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec struct {
+		TargetNamespace string `json:"targetNamespace,omitempty"`
+		Decryption      *struct {
+			Provider  string `json:"provider"`
+			SecretRef *struct {
+				Name string `json:"name"`
+			} `json:"secretRef,omitempty"`
+		} `json:"decryption,omitempty"`
+	} `json:"spec"`
+}
+
+// Migrate converts the resources of other SOPS operators into resources of
+// this operator:
+//   - SopsSecrets of the isindir sops-secrets-operator and SOPS encrypted
+//     v1/Secrets, as decrypted by Flux, are decrypted with the local keys,
+//     converted into SopsSecrets and re-encrypted with the keys of the matching
+//     .sops.yaml creation rule. The SOPS metadata covers the structure of the
+//     document, so it can not be kept.
+//   - Flux Kustomizations with SOPS decryption are converted into a
+//     SopsProvider per decryption Secret, selecting SopsSecrets in the target
+//     namespaces.
+//   - Flux decryption Secrets are used as keys for the decryption.
+func Migrate(sources []MigrationSource, opts MigrateOptions) (*Migration, error) {
+	if opts.Decryptor == nil {
+		return nil, errors.New("migration requires a decryptor")
+	}
+
+	objects := make([]migrationObject, 0)
+
+	for _, source := range sources {
+		parsed, err := readMigrationObjects(source)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, parsed...)
+	}
+
+	migration := &Migration{}
+
+	providers, decryptionSecrets, err := migrateKustomizations(objects, migration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys must be imported before the first decryption
+	for _, obj := range objects {
+		if obj.gvk.Group != "" || obj.gvk.Kind != "Secret" || hasSopsMetadata(obj.object) {
+			continue
+		}
+
+		data, err := plainSecretData(obj.object)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", obj.name(), err)
+		}
+
+		key := metadataString(obj.object, "namespace") + "/" + metadataString(obj.object, "name")
+		if _, ok := decryptionSecrets[key]; !ok && !hasDecryptionKeys(data) {
+			continue
+		}
+
+		decryptionSecrets[key] = true
+
+		if err := opts.Decryptor.KeysFromData(data, fmt.Sprintf("decryption Secret '%s'", key)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, provider := range providers {
+		manifest, err := Manifest(provider)
+		if err != nil {
+			return nil, err
+		}
+
+		migration.Manifests = append(migration.Manifests, manifest)
+	}
+
+	for _, obj := range objects {
+		var (
+			secret *sopsv1alpha1.SopsSecret
+			err    error
+		)
+
+		switch {
+		case obj.gvk.Group == isindirGroup && obj.gvk.Kind == "SopsSecret":
+			secret, err = migrateIsindirSopsSecret(obj, opts.Decryptor, migration)
+		case obj.gvk.Group == "" && obj.gvk.Kind == "Secret" && hasSopsMetadata(obj.object):
+			secret, err = migrateEncryptedSecret(obj, opts.Decryptor)
+		case obj.gvk.Group == "" && obj.gvk.Kind == "Secret":
+			key := metadataString(obj.object, "namespace") + "/" + metadataString(obj.object, "name")
+			if !decryptionSecrets[key] {
+				migration.Notes = append(migration.Notes, fmt.Sprintf("%s: skipped, the Secret is not encrypted", obj.name()))
+			}
+
+			continue
+		case obj.gvk.Group == fluxKustomizeGroup && obj.gvk.Kind == "Kustomization":
+			continue
+		default:
+			migration.Notes = append(migration.Notes, fmt.Sprintf("%s: skipped, the kind is not migrated", obj.name()))
+
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", obj.name(), err)
+		}
+
+		manifest, err := Manifest(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		path := obj.path
+		if path == "" {
+			path = secret.Name + ".yaml"
+		}
+
+		if manifest, err = Encrypt(manifest, opts.ConfigPath, path); err != nil {
+			return nil, fmt.Errorf("%s: %w", obj.name(), err)
+		}
+
+		migration.Manifests = append(migration.Manifests, manifest)
+	}
+
+	return migration, nil
+}
+
+// Write the manifests as a multi-document YAML stream.
+func (m *Migration) Write(w io.Writer) error {
+	for i, manifest := range m.Manifests {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := w.Write(manifest); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reads the documents of a source, expanding Lists.
+func readMigrationObjects(source MigrationSource) ([]migrationObject, error) {
+	objects := make([]migrationObject, 0)
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(source.Content)))
+
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", sourceName(source), err)
+		}
+
+		var object map[string]any
+		if err := yaml.Unmarshal(document, &object); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", sourceName(source), err)
+		}
+
+		if len(object) == 0 {
+			continue
+		}
+
+		items := []any{object}
+		if kind, _ := object["kind"].(string); strings.HasSuffix(kind, "List") {
+			items, _ = object["items"].([]any)
+		}
+
+		for _, item := range items {
+			object, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			apiVersion, _ := object["apiVersion"].(string)
+
+			// Not a Kubernetes object, such as a .sops.yaml in a directory
+			kind, _ := object["kind"].(string)
+			if kind == "" {
+				continue
+			}
+
+			objects = append(objects, migrationObject{
+				path:   source.Path,
+				object: object,
+				gvk:    schema.FromAPIVersionAndKind(apiVersion, kind),
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+func sourceName(source MigrationSource) string {
+	if source.Path == "" {
+		return "stdin"
+	}
+
+	return fmt.Sprintf("%q", source.Path)
+}
+
+// Converts the Flux Kustomizations with SOPS decryption into SopsProviders,
+// one per decryption Secret. Returns the providers and the decryption Secrets
+// by namespace/name.
+func migrateKustomizations(
+	objects []migrationObject,
+	migration *Migration,
+) ([]*sopsv1alpha1.SopsProvider, map[string]bool, error) {
+	providers := make([]*sopsv1alpha1.SopsProvider, 0)
+	byKey := make(map[string]*sopsv1alpha1.SopsProvider)
+	namespaces := make(map[string][]string)
+	decryptionSecrets := make(map[string]bool)
+
+	for _, obj := range objects {
+		if obj.gvk.Group != fluxKustomizeGroup || obj.gvk.Kind != "Kustomization" {
+			continue
+		}
+
+		var kustomization fluxKustomization
+		if err := convertObject(obj.object, &kustomization); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", obj.name(), err)
+		}
+
+		decryption := kustomization.Spec.Decryption
+		if decryption == nil || decryption.Provider != fluxSopsProvider {
+			continue
+		}
+
+		if decryption.SecretRef == nil || decryption.SecretRef.Name == "" {
+			migration.Notes = append(migration.Notes, fmt.Sprintf(
+				"%s: skipped, decryption without secretRef uses the credentials of the kustomize-controller, which must be granted to the sops-operator",
+				obj.name()))
+
+			continue
+		}
+
+		secretName := decryption.SecretRef.Name
+		if errs := validation.IsValidLabelValue(secretName); len(errs) > 0 {
+			return nil, nil, fmt.Errorf("%s: decryption Secret name %q is not a valid label value: %s",
+				obj.name(), secretName, strings.Join(errs, ", "))
+		}
+
+		target := kustomization.Spec.TargetNamespace
+		if target == "" {
+			target = kustomization.Namespace
+
+			migration.Notes = append(migration.Notes, fmt.Sprintf(
+				"%s: no targetNamespace, the SopsProvider only selects SopsSecrets in namespace %q",
+				obj.name(), target))
+		}
+
+		key := kustomization.Namespace + "/" + secretName
+		decryptionSecrets[key] = true
+
+		provider, ok := byKey[key]
+		if !ok {
+			provider = &sopsv1alpha1.SopsProvider{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: sopsv1alpha1.GroupVersion.String(),
+					Kind:       "SopsProvider",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("flux-%s-%s", kustomization.Namespace, secretName),
+				},
+				Spec: sopsv1alpha1.SopsProviderSpec{
+					ProviderSecrets: []*api.NamespacedSelector{{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{meta.KeySecretLabel: secretName},
+						},
+						NamespaceSelector: namespaceNameSelector(kustomization.Namespace),
+					}},
+				},
+			}
+
+			byKey[key] = provider
+			providers = append(providers, provider)
+
+			migration.Notes = append(migration.Notes, fmt.Sprintf(
+				"Secret %s: label the decryption Secret, so it is selected by SopsProvider %s: kubectl label secret %s -n %s %s=%s",
+				key, provider.Name, secretName, kustomization.Namespace, meta.KeySecretLabel, secretName))
+		}
+
+		if !slices.Contains(namespaces[key], target) {
+			namespaces[key] = append(namespaces[key], target)
+		}
+	}
+
+	for key, provider := range byKey {
+		targets := namespaces[key]
+		slices.Sort(targets)
+
+		provider.Spec.SOPSSelectors = []*api.NamespacedSelector{{
+			NamespaceSelector: namespaceNameSelector(targets...),
+		}}
+	}
+
+	return providers, decryptionSecrets, nil
+}
+
+// Selects namespaces by name.
+func namespaceNameSelector(names ...string) *metav1.LabelSelector {
+	if len(names) == 1 {
+		return &metav1.LabelSelector{
+			MatchLabels: map[string]string{corev1.LabelMetadataName: names[0]},
+		}
+	}
+
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      corev1.LabelMetadataName,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   names,
+		}},
+	}
+}
+
+func migrateIsindirSopsSecret(
+	obj migrationObject,
+	dec *decryptor.SOPSDecryptor,
+	migration *Migration,
+) (*sopsv1alpha1.SopsSecret, error) {
+	var source isindirSopsSecret
+	if err := decryptObject(obj.object, dec, &source); err != nil {
+		return nil, err
+	}
+
+	if source.Spec.Suspend {
+		migration.Notes = append(migration.Notes, fmt.Sprintf(
+			"%s: suspended, the migrated SopsSecret is reconciled once applied", obj.name()))
+	}
+
+	secret := &sopsv1alpha1.SopsSecret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: sopsv1alpha1.GroupVersion.String(),
+			Kind:       "SopsSecret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        source.Name,
+			Namespace:   source.Namespace,
+			Labels:      copyMetadata(source.Labels),
+			Annotations: copyMetadata(source.Annotations),
+		},
+		Spec: sopsv1alpha1.SopsSecretSpec{
+			Secrets: make([]*sopsv1alpha1.SopsSecretItem, 0, len(source.Spec.SecretTemplates)),
+		},
+	}
+
+	for _, template := range source.Spec.SecretTemplates {
+		secret.Spec.Secrets = append(secret.Spec.Secrets, &sopsv1alpha1.SopsSecretItem{
+			Name:        template.Name,
+			Labels:      copyMetadata(template.Labels),
+			Annotations: copyMetadata(template.Annotations),
+			Type:        template.Type,
+			Data:        template.Data,
+			StringData:  template.StringData,
+		})
+	}
+
+	return secret, nil
+}
+
+func migrateEncryptedSecret(obj migrationObject, dec *decryptor.SOPSDecryptor) (*sopsv1alpha1.SopsSecret, error) {
+	var source corev1.Secret
+	if err := decryptObject(obj.object, dec, &source); err != nil {
+		return nil, err
+	}
+
+	converted, err := Convert(&source, ConvertOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	secret, _ := converted.(*sopsv1alpha1.SopsSecret)
+
+	return secret, nil
+}
+
+// Decrypts a SOPS encrypted object into out. The MAC is not verified, as the
+// object may have been modified by the API server.
+func decryptObject(object map[string]any, dec *decryptor.SOPSDecryptor, out any) error {
+	if !hasSopsMetadata(object) {
+		return errors.New("no sops metadata")
+	}
+
+	encrypted, err := yaml.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	decrypted, err := dec.SopsDecryptWithFormat(context.Background(), encrypted, logr.Discard(), formats.Yaml, formats.Yaml)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(decrypted, out)
+}
+
+func convertObject(object map[string]any, out any) error {
+	raw, err := yaml.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(raw, out)
+}
+
+// Decoded data and stringData of a plain Secret.
+func plainSecretData(object map[string]any) (map[string][]byte, error) {
+	var secret corev1.Secret
+	if err := convertObject(object, &secret); err != nil {
+		return nil, err
+	}
+
+	data := secret.Data
+	if data == nil {
+		data = make(map[string][]byte, len(secret.StringData))
+	}
+
+	for key, value := range secret.StringData {
+		data[key] = []byte(value)
+	}
+
+	return data, nil
+}
+
+// Whether the data contains entries named like the keys of a decryption
+// Secret.
+func hasDecryptionKeys(data map[string][]byte) bool {
+	for name := range data {
+		switch {
+		case filepath.Ext(name) == decryptor.DecryptionPGPExt,
+			filepath.Ext(name) == decryptor.DecryptionAgeExt,
+			name == decryptor.DecryptionVaultTokenFileName,
+			name == decryptor.DecryptionAWSKmsFile,
+			name == decryptor.DecryptionAzureAuthFile,
+			name == decryptor.DecryptionGCPCredsFile:
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasSopsMetadata(object map[string]any) bool {
+	_, ok := object["sops"].(map[string]any)
+
+	return ok
+}
+
+func metadataString(object map[string]any, field string) string {
+	metadata, _ := object["metadata"].(map[string]any)
+	value, _ := metadata[field].(string)
+
+	return value
+}
@@ -0,0 +1,203 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	"github.com/peak-scale/sops-operator/internal/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".sops.yaml"), []byte(`creation_rules:
+  - path_regex: \.yaml$
+    age: `+testAgeRecipient+`
+`), 0o644))
+
+	key, err := os.ReadFile("testdata/age.agekey")
+	require.NoError(t, err)
+
+	// Encrypted with the original schemas
+	isindir, err := Encrypt([]byte(`apiVersion: isindir.github.com/v1alpha3
+kind: SopsSecret
+metadata:
+  name: app
+  namespace: team
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+spec:
+  suspend: true
+  secretTemplates:
+    - name: app-credentials
+      labels:
+        app: app
+      stringData:
+        password: secret
+    - name: app-tls
+      type: kubernetes.io/tls
+      data:
+        tls.crt: Y2VydA==
+        tls.key: a2V5
+`), "", filepath.Join(dir, "app.yaml"))
+	require.NoError(t, err)
+
+	flux, err := Encrypt([]byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: database
+  namespace: team
+stringData:
+  user: admin
+`), "", filepath.Join(dir, "database.yaml"))
+	require.NoError(t, err)
+
+	decryption := `apiVersion: v1
+kind: Secret
+metadata:
+  name: sops-age
+  namespace: flux-system
+data:
+  age.agekey: ` + base64.StdEncoding.EncodeToString(key) + `
+`
+
+	kustomizations := `apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: team
+  namespace: flux-system
+spec:
+  targetNamespace: team
+  decryption:
+    provider: sops
+    secretRef:
+      name: sops-age
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: platform
+  namespace: flux-system
+spec:
+  targetNamespace: platform
+  decryption:
+    provider: sops
+    secretRef:
+      name: sops-age
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1
+kind: Kustomization
+metadata:
+  name: ambient
+  namespace: flux-system
+spec:
+  decryption:
+    provider: sops
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: team
+`
+
+	dec, cleanup, err := decryptor.NewSOPSTempDecryptor()
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	migration, err := Migrate([]MigrationSource{
+		{Path: filepath.Join(dir, "app.yaml"), Content: isindir},
+		{Path: filepath.Join(dir, "database.yaml"), Content: flux},
+		{Path: filepath.Join(dir, "flux.yaml"), Content: []byte(kustomizations + "---\n" + decryption)},
+	}, MigrateOptions{Decryptor: dec})
+	require.NoError(t, err)
+	require.Len(t, migration.Manifests, 3)
+
+	var provider sopsv1alpha1.SopsProvider
+	require.NoError(t, yaml.Unmarshal(migration.Manifests[0], &provider))
+	require.Equal(t, "flux-flux-system-sops-age", provider.Name)
+	require.Equal(t, map[string]string{meta.KeySecretLabel: "sops-age"}, provider.Spec.ProviderSecrets[0].MatchLabels)
+	require.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      "kubernetes.io/metadata.name",
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{"platform", "team"},
+	}}, provider.Spec.SOPSSelectors[0].NamespaceSelector.MatchExpressions)
+
+	check := decryptor.NewSOPSDecryptor("")
+	require.NoError(t, check.AddAgeKey(key))
+
+	var app sopsv1alpha1.SopsSecret
+	require.NoError(t, yaml.Unmarshal(migration.Manifests[1], &app))
+	require.Equal(t, "team", app.Namespace)
+	require.Equal(t, "app", app.Name)
+	require.Empty(t, app.Annotations)
+	require.Equal(t, EncryptedRegex, app.Sops.EncryptedRegex)
+	require.Len(t, app.Spec.Secrets, 2)
+
+	for _, item := range app.Spec.Secrets {
+		require.NoError(t, check.Decrypt(context.Background(), app.Sops, item, logr.Discard()))
+	}
+
+	require.Equal(t, "app", app.Spec.Secrets[0].Labels["app"])
+	require.Equal(t, "secret", app.Spec.Secrets[0].StringData["password"])
+	require.Equal(t, "kubernetes.io/tls", string(app.Spec.Secrets[1].Type))
+	require.Equal(t, "a2V5", app.Spec.Secrets[1].Data["tls.key"])
+
+	var database sopsv1alpha1.SopsSecret
+	require.NoError(t, yaml.Unmarshal(migration.Manifests[2], &database))
+	require.Equal(t, "database", database.Name)
+	require.NoError(t, check.Decrypt(context.Background(), database.Sops, database.Spec.Secrets[0], logr.Discard()))
+	require.Equal(t, "admin", database.Spec.Secrets[0].StringData["user"])
+
+	notes := strings.Join(migration.Notes, "\n")
+	require.Contains(t, notes, "kubectl label secret sops-age -n flux-system sops.addons.projectcapsule.dev=sops-age")
+	require.Contains(t, notes, "Kustomization flux-system/ambient: skipped")
+	require.Contains(t, notes, "SopsSecret team/app: suspended")
+	require.Contains(t, notes, "ConfigMap team/settings: skipped")
+	require.NotContains(t, notes, "sops-age: skipped")
+
+	var out bytes.Buffer
+	require.NoError(t, migration.Write(&out))
+	require.Equal(t, 2, strings.Count(out.String(), "\n---\n"))
+}
+
+func TestMigrateWithoutKeys(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".sops.yaml"), []byte(`creation_rules:
+  - age: `+testAgeRecipient+`
+`), 0o644))
+
+	flux, err := Encrypt([]byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: database
+stringData:
+  user: admin
+`), "", filepath.Join(dir, "database.yaml"))
+	require.NoError(t, err)
+
+	dec, cleanup, err := decryptor.NewSOPSTempDecryptor()
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	_, err = Migrate([]MigrationSource{{Path: filepath.Join(dir, "database.yaml"), Content: flux}}, MigrateOptions{Decryptor: dec})
+	require.ErrorContains(t, err, "Secret database")
+}
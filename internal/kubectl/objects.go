@@ -0,0 +1,90 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// ReadSecret reads a v1/Secret manifest.
+func ReadSecret(r io.Reader) (*corev1.Secret, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret corev1.Secret
+	if err := yaml.Unmarshal(content, &secret); err != nil {
+		return nil, fmt.Errorf("parse secret: %w", err)
+	}
+
+	if secret.Kind != "Secret" {
+		return nil, fmt.Errorf("expected a Secret, got %q", secret.Kind)
+	}
+
+	return &secret, nil
+}
+
+// ReadSopsSecret reads a SopsSecret or GlobalSopsSecret manifest.
+func ReadSopsSecret(r io.Reader) (api.SopsImplementation, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(content, &typeMeta); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	var obj api.SopsImplementation
+
+	switch typeMeta.Kind {
+	case "SopsSecret":
+		obj = &sopsv1alpha1.SopsSecret{}
+	case "GlobalSopsSecret":
+		obj = &sopsv1alpha1.GlobalSopsSecret{}
+	default:
+		return nil, fmt.Errorf("expected a SopsSecret or GlobalSopsSecret, got %q", typeMeta.Kind)
+	}
+
+	if err := yaml.Unmarshal(content, obj); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", typeMeta.Kind, err)
+	}
+
+	return obj, nil
+}
+
+// GetSopsSecret retrieves a SopsSecret, or a GlobalSopsSecret when namespace
+// is empty.
+func GetSopsSecret(ctx context.Context, c client.Client, name, namespace string) (api.SopsImplementation, error) {
+	var obj api.SopsImplementation = &sopsv1alpha1.GlobalSopsSecret{}
+	if namespace != "" {
+		obj = &sopsv1alpha1.SopsSecret{}
+	}
+
+	if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, obj); err != nil {
+		return nil, err
+	}
+
+	// Typed clients do not populate the kind
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return obj, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provider is a SopsProvider or NamespacedSopsProvider selecting an object.
+type Provider struct {
+	// Name of the provider, prefixed with the namespace for
+	// NamespacedSopsProviders
+	Name   string
+	Status sopsv1alpha1.SopsProviderStatus
+
+	keySecretRefs    []*api.KeySecretReference
+	selectKeySecrets func(ctx context.Context, c client.Client, candidates []*corev1.Secret) (map[string]*corev1.Secret, error)
+}
+
+// MatchingProviders returns the SopsProviders and NamespacedSopsProviders
+// whose selectors match the SopsSecret or GlobalSopsSecret, the same way the
+// controller selects them. The object does not need to exist in the cluster.
+func MatchingProviders(ctx context.Context, c client.Client, secret metav1.Object) ([]Provider, error) {
+	providerList := &sopsv1alpha1.SopsProviderList{}
+	if err := c.List(ctx, providerList); err != nil {
+		return nil, fmt.Errorf("list providers: %w", err)
+	}
+
+	providers := make([]Provider, 0)
+
+	for i := range providerList.Items {
+		provider := &providerList.Items[i]
+		if provider.SelectsSecret(ctx, c, secret) {
+			providers = append(providers, Provider{
+				Name:             provider.Name,
+				Status:           provider.Status,
+				keySecretRefs:    provider.Spec.KeySecretRefs,
+				selectKeySecrets: provider.SelectKeySecrets,
+			})
+		}
+	}
+
+	// Namespaced providers only serve SopsSecrets
+	if secret.GetNamespace() == "" {
+		return providers, nil
+	}
+
+	namespacedList := &sopsv1alpha1.NamespacedSopsProviderList{}
+	if err := c.List(ctx, namespacedList); err != nil {
+		return nil, fmt.Errorf("list namespaced providers: %w", err)
+	}
+
+	for i := range namespacedList.Items {
+		provider := &namespacedList.Items[i]
+		if provider.SelectsSecret(ctx, c, secret) {
+			providers = append(providers, Provider{
+				Name:   provider.ProviderName(),
+				Status: provider.Status,
+				selectKeySecrets: func(_ context.Context, _ client.Client, candidates []*corev1.Secret) (map[string]*corev1.Secret, error) {
+					return provider.SelectKeySecrets(candidates)
+				},
+			})
+		}
+	}
+
+	return providers, nil
+}
+
+// WriteProviders writes the providers with their readiness and key Secrets.
+func WriteProviders(w io.Writer, providers []Provider) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	if _, err := fmt.Fprintln(tw, "PROVIDER\tREADY\tKEY SECRETS"); err != nil {
+		return err
+	}
+
+	for _, provider := range providers {
+		ready := string(metav1.ConditionUnknown)
+		if condition := provider.Status.Conditions.GetConditionByType(capmeta.ReadyCondition); condition != nil {
+			ready = string(condition.Status)
+		}
+
+		loaded := 0
+
+		for _, key := range provider.Status.Providers {
+			if key.Status == metav1.ConditionTrue {
+				loaded++
+			}
+		}
+
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%d/%d\n", provider.Name, ready, loaded, len(provider.Status.Providers)); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
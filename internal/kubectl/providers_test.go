@@ -0,0 +1,82 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package kubectl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	"github.com/peak-scale/sops-operator/internal/api"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMatchingProviders(t *testing.T) {
+	t.Parallel()
+
+	selecting := &sopsv1alpha1.SopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "selecting"},
+		Spec: sopsv1alpha1.SopsProviderSpec{
+			SOPSSelectors: []*api.NamespacedSelector{{
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"sops": "enabled"}},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "app"}},
+			}},
+		},
+		Status: sopsv1alpha1.SopsProviderStatus{
+			Conditions: capmeta.ConditionList{{
+				Type:   capmeta.ReadyCondition,
+				Status: metav1.ConditionTrue,
+			}},
+			Providers: []*sopsv1alpha1.SopsProviderItemStatus{
+				{Origin: api.Origin{Name: "key"}, Condition: metav1.Condition{Status: metav1.ConditionTrue}},
+				{Origin: api.Origin{Name: "broken"}, Condition: metav1.Condition{Status: metav1.ConditionFalse}},
+			},
+		},
+	}
+
+	other := &sopsv1alpha1.SopsProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec: sopsv1alpha1.SopsProviderSpec{
+			SOPSSelectors: []*api.NamespacedSelector{{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "other"}},
+			}},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(Scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app", Labels: map[string]string{"team": "app"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"team": "other"}}},
+		selecting,
+		other,
+	).Build()
+
+	secret := &sopsv1alpha1.SopsSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "database",
+			Namespace: "app",
+			Labels:    map[string]string{"sops": "enabled"},
+		},
+	}
+
+	providers, err := MatchingProviders(context.Background(), c, secret)
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	require.Equal(t, "selecting", providers[0].Name)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteProviders(&out, providers))
+	require.Equal(t, "PROVIDER   READY  KEY SECRETS\nselecting  True   1/2\n", out.String())
+
+	secret.Labels = nil
+
+	providers, err = MatchingProviders(context.Background(), c, secret)
+	require.NoError(t, err)
+	require.Empty(t, providers)
+}
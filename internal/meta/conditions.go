@@ -4,7 +4,11 @@
 package meta
 
 import (
+	"fmt"
+	"strings"
+
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -29,6 +33,18 @@ const (
 
 	// SecretsReplicationFailedReason indicates a condition or event observed a failure.
 	SecretsReplicationFailedReason string = "ReplicationFailure"
+
+	// DriftedCondition indicates generated secrets deviate from their desired state.
+	DriftedCondition string = "Drifted"
+
+	// InSyncReason indicates all generated secrets match their desired state.
+	InSyncReason string = "InSync"
+
+	// DriftDetectedReason indicates drift was observed and left in place.
+	DriftDetectedReason string = "DriftDetected"
+
+	// DriftRepairedReason indicates drift was observed and repaired.
+	DriftRepairedReason string = "DriftRepaired"
 )
 
 // Should be used on translator level.
@@ -85,3 +101,41 @@ func NewNotReadySecretStatusCondition(obj client.Object, msg string) *sopsv1alph
 		},
 	}
 }
+
+// NewDriftedCondition summarizes the drift observed on the generated secrets.
+// Drift which has been repaired is reported with a False status. The last
+// transition time of the current condition is kept while the status does not
+// change.
+func NewDriftedCondition(
+	obj client.Object,
+	conditions capmeta.ConditionList,
+	drifted []string,
+	repaired bool,
+) capmeta.Condition {
+	condition := capmeta.Condition{
+		Type:               DriftedCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             InSyncReason,
+		Message:            "Secrets in sync",
+	}
+
+	switch {
+	case len(drifted) == 0:
+	case repaired:
+		condition.Reason = DriftRepairedReason
+		condition.Message = fmt.Sprintf("Repaired drift on secrets: %s", strings.Join(drifted, ", "))
+	default:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = DriftDetectedReason
+		condition.Message = fmt.Sprintf("Drift detected on secrets: %s", strings.Join(drifted, ", "))
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+
+	if current := conditions.GetConditionByType(DriftedCondition); current != nil && current.Status == condition.Status {
+		condition.LastTransitionTime = current.LastTransitionTime
+	}
+
+	return condition
+}
@@ -5,8 +5,10 @@ package meta_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/peak-scale/sops-operator/internal/meta"
+	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,3 +47,48 @@ func TestNewNotReadySecretStatusConditionUsesReadyType(t *testing.T) {
 	require.Equal(t, "my-secret", item.Name)
 	require.Equal(t, "default", item.Namespace)
 }
+
+func TestNewDriftedCondition(t *testing.T) {
+	t.Parallel()
+
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+
+	inSync := meta.NewDriftedCondition(obj, nil, nil, true)
+	require.Equal(t, meta.DriftedCondition, inSync.Type)
+	require.Equal(t, metav1.ConditionFalse, inSync.Status)
+	require.Equal(t, meta.InSyncReason, inSync.Reason)
+	require.Equal(t, int64(2), inSync.ObservedGeneration)
+
+	repaired := meta.NewDriftedCondition(obj, nil, []string{"default/a", "default/b"}, true)
+	require.Equal(t, metav1.ConditionFalse, repaired.Status)
+	require.Equal(t, meta.DriftRepairedReason, repaired.Reason)
+	require.Contains(t, repaired.Message, "default/a, default/b")
+
+	reported := meta.NewDriftedCondition(obj, nil, []string{"default/a"}, false)
+	require.Equal(t, metav1.ConditionTrue, reported.Status)
+	require.Equal(t, meta.DriftDetectedReason, reported.Reason)
+	require.Contains(t, reported.Message, "default/a")
+}
+
+func TestNewDriftedConditionKeepsTransitionTime(t *testing.T) {
+	t.Parallel()
+
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+	transition := metav1.NewTime(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	conditions := capmeta.ConditionList{{
+		Type:               meta.DriftedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             meta.InSyncReason,
+		LastTransitionTime: transition,
+	}}
+
+	repaired := meta.NewDriftedCondition(obj, conditions, []string{"default/a"}, true)
+	require.Equal(t, transition, repaired.LastTransitionTime)
+
+	conditions.UpdateConditionByType(repaired)
+	require.Equal(t, transition, conditions.GetConditionByType(meta.DriftedCondition).LastTransitionTime)
+
+	reported := meta.NewDriftedCondition(obj, conditions, []string{"default/a"}, false)
+	require.NotEqual(t, transition, reported.LastTransitionTime)
+}
@@ -0,0 +1,9 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package meta
+
+const (
+	// Finalizer removing merged keys from secrets not owned by the operator.
+	MergeFinalizer = "sops.addons.projectcapsule.dev/merge"
+)
@@ -3,8 +3,56 @@
 
 package meta
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
 const (
 	// This is mainly to keep reconciles performance.
 	//nolint:gosec
 	KeySecretLabel = "sops.addons.projectcapsule.dev"
+
+	// Label of generated secrets in the restricted secret cache mode, where
+	// only secrets with it are cached.
+	GeneratedSecretLabel = "sops.addons.projectcapsule.dev/generated"
+
+	// Shard of a SopsSecret, reconciled by the replica started with the same
+	// shard.
+	ShardLabel = "sops.addons.projectcapsule.dev/shard"
+
+	// Checksum of the content last written to a generated secret.
+	ChecksumAnnotation = "sops.addons.projectcapsule.dev/checksum"
+
+	// Set to "true" by the owner of a secret to allow merged SopsSecrets to
+	// take over keys managed by others.
+	MergeForceAnnotation = "sops.addons.projectcapsule.dev/merge-force"
 )
+
+// IsKeySecret returns true for Secrets labeled as key Secrets.
+func IsKeySecret(obj metav1.Object) bool {
+	_, ok := obj.GetLabels()[KeySecretLabel]
+
+	return ok
+}
+
+// KeySecretSelector selects the Secrets labeled as key Secrets.
+func KeySecretSelector() labels.Selector {
+	return labels.NewSelector().Add(mustRequirement(KeySecretLabel, selection.Exists))
+}
+
+// GeneratedSecretSelector selects the generated secrets labeled for the
+// restricted secret cache mode.
+func GeneratedSecretSelector() labels.Selector {
+	return labels.NewSelector().Add(mustRequirement(GeneratedSecretLabel, selection.Exists))
+}
+
+func mustRequirement(key string, op selection.Operator, values ...string) labels.Requirement {
+	req, err := labels.NewRequirement(key, op, values)
+	if err != nil {
+		panic(err)
+	}
+
+	return *req
+}
@@ -0,0 +1,51 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package meta
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestKeySecretLabelSelection(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		labels    map[string]string
+		keySecret bool
+		generated bool
+	}{
+		"key secret": {
+			labels:    map[string]string{KeySecretLabel: "true"},
+			keySecret: true,
+		},
+		"key secret with any value": {
+			labels:    map[string]string{KeySecretLabel: "generated"},
+			keySecret: true,
+		},
+		"generated secret": {
+			labels:    map[string]string{GeneratedSecretLabel: "true"},
+			generated: true,
+		},
+		"unlabeled secret": {
+			labels: map[string]string{"app": "demo"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			obj := &metav1.ObjectMeta{Labels: tt.labels}
+
+			require.Equal(t, tt.keySecret, IsKeySecret(obj))
+			require.Equal(t, tt.keySecret, KeySecretSelector().Matches(labels.Set(tt.labels)))
+			require.Equal(t, tt.generated, GeneratedSecretSelector().Matches(labels.Set(tt.labels)))
+		})
+	}
+}
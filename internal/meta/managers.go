@@ -0,0 +1,9 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package meta
+
+const (
+	// Field manager used to server-side apply generated secrets.
+	FieldManager = "sops-operator"
+)
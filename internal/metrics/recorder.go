@@ -4,17 +4,69 @@
 package metrics
 
 import (
+	"slices"
+	"sync"
+	"time"
+
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
 	"github.com/peak-scale/sops-operator/internal/meta"
 	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	crtlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// Operations on generated Secrets.
+const (
+	SecretOperationCreate = "create"
+	SecretOperationUpdate = "update"
+	SecretOperationDelete = "delete"
+)
+
+// Reasons of failed decryptions detected before decrypting, the decryptor
+// reports the reasons of failed decryptions.
+const (
+	DecryptionFailureNoProvider   = "no_provider"
+	DecryptionFailureNotEncrypted = "not_encrypted"
+)
+
+// Buckets for decryptions and key service calls, from 5ms to ~20s to cover
+// remote KMS calls.
+var latencyBuckets = prometheus.ExponentialBuckets(0.005, 2, 13)
+
 type Recorder struct {
-	providerConditionGauge     *prometheus.GaugeVec
-	secretConditionGauge       *prometheus.GaugeVec
-	globalSecretConditionGauge *prometheus.GaugeVec
+	providerConditionGauge           *prometheus.GaugeVec
+	namespacedProviderConditionGauge *prometheus.GaugeVec
+	secretConditionGauge             *prometheus.GaugeVec
+	globalSecretConditionGauge       *prometheus.GaugeVec
+	decryptionDuration               *prometheus.HistogramVec
+	keyServiceDuration               *prometheus.HistogramVec
+	decryptionFailures               *prometheus.CounterVec
+	secretOperations                 *prometheus.CounterVec
+	keySecretLoadedGauge             *prometheus.GaugeVec
+	keySecretIdentitiesGauge         *prometheus.GaugeVec
+	pgpKeyExpiryGauge                *prometheus.GaugeVec
+	vaultTokenExpiryGauge            *prometheus.GaugeVec
+	providerSecretsGauge             *prometheus.GaugeVec
+
+	// Providers selecting each SopsSecret and GlobalSopsSecret and the number
+	// of secrets served by each provider
+	servedMu    sync.Mutex
+	served      map[string][]string
+	servedCount map[string]int
+}
+
+// KeySecretState is the state of a key Secret loaded by a provider.
+type KeySecretState struct {
+	// Loaded is true when the keys were loaded successfully.
+	Loaded bool
+	// Identities counts the keys and credentials by key type.
+	Identities map[string]int
+	// PGPExpiry is the expiry of the PGP keys by fingerprint.
+	PGPExpiry map[string]time.Time
+	// VaultTokenExpiry is the expiry of the Vault token, zero if unknown.
+	VaultTokenExpiry time.Time
 }
 
 func MustMakeRecorder() *Recorder {
@@ -36,6 +88,14 @@ func NewRecorder() *Recorder {
 			},
 			[]string{"name", "status"},
 		),
+		namespacedProviderConditionGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "namespaced_provider_condition",
+				Help:      "The current condition status of a Namespaced Provider.",
+			},
+			[]string{"name", "namespace", "status"},
+		),
 		secretConditionGauge: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: namespace,
@@ -52,17 +112,228 @@ func NewRecorder() *Recorder {
 			},
 			[]string{"name", "status"},
 		),
+		decryptionDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "decryption_duration_seconds",
+				Help:      "Duration of decryptions by the type of the key which decrypted the data key.",
+				Buckets:   latencyBuckets,
+			},
+			[]string{"key_type"},
+		),
+		keyServiceDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "key_service_request_duration_seconds",
+				Help:      "Duration of data key decryptions by the key service, by key type and result.",
+				Buckets:   latencyBuckets,
+			},
+			[]string{"key_type", "result"},
+		),
+		decryptionFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "decryption_failures_total",
+				Help:      "Total number of failed decryptions by reason.",
+			},
+			[]string{"reason"},
+		),
+		secretOperations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "secret_operations_total",
+				Help:      "Total number of create, update and delete operations on generated Secrets.",
+			},
+			[]string{"operation"},
+		),
+		keySecretLoadedGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "provider_key_secret_loaded",
+				Help:      "Whether the keys of a key Secret were loaded by a Provider.",
+			},
+			[]string{"provider", "secret", "namespace"},
+		),
+		keySecretIdentitiesGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "provider_key_secret_identities",
+				Help:      "The number of keys and credentials of a key Secret by key type.",
+			},
+			[]string{"provider", "secret", "namespace", "key_type"},
+		),
+		pgpKeyExpiryGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "provider_key_secret_pgp_key_expiry_timestamp_seconds",
+				Help:      "The expiry of a PGP key of a key Secret as unix timestamp.",
+			},
+			[]string{"provider", "secret", "namespace", "fingerprint"},
+		),
+		vaultTokenExpiryGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "provider_key_secret_vault_token_expiry_timestamp_seconds",
+				Help:      "The expiry of the Vault token of a key Secret as unix timestamp.",
+			},
+			[]string{"provider", "secret", "namespace"},
+		),
+		providerSecretsGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "provider_secrets",
+				Help:      "The number of SopsSecrets and GlobalSopsSecrets selected by a Provider.",
+			},
+			[]string{"name"},
+		),
+		served:      make(map[string][]string),
+		servedCount: make(map[string]int),
 	}
 }
 
 func (r *Recorder) Collectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		r.providerConditionGauge,
+		r.namespacedProviderConditionGauge,
 		r.secretConditionGauge,
 		r.globalSecretConditionGauge,
+		r.decryptionDuration,
+		r.keyServiceDuration,
+		r.decryptionFailures,
+		r.secretOperations,
+		r.keySecretLoadedGauge,
+		r.keySecretIdentitiesGauge,
+		r.pgpKeyExpiryGauge,
+		r.vaultTokenExpiryGauge,
+		r.providerSecretsGauge,
 	}
 }
 
+// RecordProviderKeySecret records the state of a key Secret of the provider.
+func (r *Recorder) RecordProviderKeySecret(provider string, secret *corev1.Secret, state KeySecretState) {
+	var loaded float64
+	if state.Loaded {
+		loaded = 1
+	}
+
+	r.keySecretLoadedGauge.WithLabelValues(provider, secret.Name, secret.Namespace).Set(loaded)
+
+	for keyType, count := range state.Identities {
+		r.keySecretIdentitiesGauge.WithLabelValues(provider, secret.Name, secret.Namespace, keyType).Set(float64(count))
+	}
+
+	for fingerprint, expiry := range state.PGPExpiry {
+		r.pgpKeyExpiryGauge.WithLabelValues(provider, secret.Name, secret.Namespace, fingerprint).Set(float64(expiry.Unix()))
+	}
+
+	if !state.VaultTokenExpiry.IsZero() {
+		r.vaultTokenExpiryGauge.WithLabelValues(provider, secret.Name, secret.Namespace).Set(float64(state.VaultTokenExpiry.Unix()))
+	}
+}
+
+// DeleteProviderKeySecrets deletes the key Secret metrics of the provider.
+func (r *Recorder) DeleteProviderKeySecrets(provider string) {
+	for _, gauge := range []*prometheus.GaugeVec{
+		r.keySecretLoadedGauge,
+		r.keySecretIdentitiesGauge,
+		r.pgpKeyExpiryGauge,
+		r.vaultTokenExpiryGauge,
+	} {
+		gauge.DeletePartialMatch(map[string]string{"provider": provider})
+	}
+}
+
+// RecordServedSecret records the providers selecting a SopsSecret or
+// GlobalSopsSecret and updates the number of secrets served by each provider.
+func (r *Recorder) RecordServedSecret(secret client.Object, providers []string) {
+	r.servedMu.Lock()
+	defer r.servedMu.Unlock()
+
+	key := servedKey(secret)
+	previous := r.served[key]
+
+	providers = slices.Compact(slices.Sorted(slices.Values(providers)))
+
+	if len(providers) == 0 {
+		delete(r.served, key)
+	} else {
+		r.served[key] = providers
+	}
+
+	r.updateProviderSecrets(previous, providers)
+}
+
+// Forgets a deleted SopsSecret or GlobalSopsSecret.
+func (r *Recorder) forgetServedSecret(secret client.Object) {
+	r.servedMu.Lock()
+	defer r.servedMu.Unlock()
+
+	key := servedKey(secret)
+	previous := r.served[key]
+
+	delete(r.served, key)
+
+	r.updateProviderSecrets(previous, nil)
+}
+
+// Updates the served secrets of the providers which started or stopped
+// serving a secret, must be called with the lock held.
+func (r *Recorder) updateProviderSecrets(previous, current []string) {
+	for _, provider := range previous {
+		if !slices.Contains(current, provider) {
+			r.servedCount[provider]--
+			r.providerSecretsGauge.WithLabelValues(provider).Set(float64(r.servedCount[provider]))
+		}
+	}
+
+	for _, provider := range current {
+		if !slices.Contains(previous, provider) {
+			r.servedCount[provider]++
+			r.providerSecretsGauge.WithLabelValues(provider).Set(float64(r.servedCount[provider]))
+		}
+	}
+}
+
+func servedKey(secret client.Object) string {
+	switch secret.(type) {
+	case *sopsv1alpha1.GlobalSopsSecret:
+		return "GlobalSopsSecret/" + secret.GetName()
+	default:
+		return "SopsSecret/" + secret.GetNamespace() + "/" + secret.GetName()
+	}
+}
+
+// ObserveDecryption records the duration of a decryption and its failure
+// reason, if it failed.
+func (r *Recorder) ObserveDecryption(keyType string, duration time.Duration, reason string) {
+	r.decryptionDuration.WithLabelValues(keyType).Observe(duration.Seconds())
+
+	if reason != "" {
+		r.RecordDecryptionFailure(reason)
+	}
+}
+
+// ObserveKeyServiceCall records the duration of a data key decryption by the
+// key service.
+func (r *Recorder) ObserveKeyServiceCall(keyType string, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+
+	r.keyServiceDuration.WithLabelValues(keyType, result).Observe(duration.Seconds())
+}
+
+// RecordDecryptionFailure counts a failed decryption.
+func (r *Recorder) RecordDecryptionFailure(reason string) {
+	r.decryptionFailures.WithLabelValues(reason).Inc()
+}
+
+// RecordSecretOperation counts an operation on a generated Secret.
+func (r *Recorder) RecordSecretOperation(operation string) {
+	r.secretOperations.WithLabelValues(operation).Inc()
+}
+
 // RecordCondition records the condition as given for the ref.
 func (r *Recorder) RecordProviderCondition(instance *sopsv1alpha1.SopsProvider) {
 	for _, status := range []string{meta.ReadyCondition} {
@@ -90,9 +361,39 @@ func (r *Recorder) DeleteProviderConditionMetricByType(name string, condition st
 	})
 }
 
+// RecordNamespacedProviderCondition records the condition as given for the ref.
+func (r *Recorder) RecordNamespacedProviderCondition(instance *sopsv1alpha1.NamespacedSopsProvider) {
+	for _, status := range []string{meta.ReadyCondition} {
+		var value float64
+
+		cond := instance.Status.Conditions.GetConditionByType(status)
+		if cond == nil {
+			r.namespacedProviderConditionGauge.DeleteLabelValues(instance.GetName(), instance.GetNamespace(), status)
+
+			continue
+		}
+
+		if cond.Status == metav1.ConditionTrue {
+			value = 1
+		}
+
+		r.namespacedProviderConditionGauge.WithLabelValues(instance.GetName(), instance.GetNamespace(), status).Set(value)
+	}
+}
+
+// DeleteNamespacedProvider deletes the metrics of a deleted Namespaced Provider.
+func (r *Recorder) DeleteNamespacedProvider(provider *sopsv1alpha1.NamespacedSopsProvider) {
+	r.namespacedProviderConditionGauge.DeletePartialMatch(map[string]string{
+		"name":      provider.Name,
+		"namespace": provider.Namespace,
+	})
+
+	r.forgetProvider(provider.ProviderName())
+}
+
 // RecordCondition records the condition as given for the ref.
 func (r *Recorder) RecordSecretCondition(instance *sopsv1alpha1.SopsSecret) {
-	for _, status := range []string{meta.ReadyCondition} {
+	for _, status := range []string{meta.ReadyCondition, meta.DriftedCondition} {
 		var value float64
 
 		cond := instance.Status.Conditions.GetConditionByType(status)
@@ -120,7 +421,7 @@ func (r *Recorder) DeleteSecretConditionMetricByType(name string, namespace stri
 
 // RecordCondition records the condition as given for the ref.
 func (r *Recorder) RecordGlobalSecretCondition(instance *sopsv1alpha1.GlobalSopsSecret) {
-	for _, status := range []string{meta.ReadyCondition} {
+	for _, status := range []string{meta.ReadyCondition, meta.DriftedCondition} {
 		var value float64
 
 		cond := instance.Status.Conditions.GetConditionByType(status)
@@ -150,6 +451,23 @@ func (r *Recorder) DeleteProvider(provider *sopsv1alpha1.SopsProvider) {
 	r.providerConditionGauge.DeletePartialMatch(map[string]string{
 		"name": provider.Name,
 	})
+
+	r.forgetProvider(provider.Name)
+}
+
+// Deletes the key Secret and served secret metrics of a deleted provider.
+func (r *Recorder) forgetProvider(provider string) {
+	r.DeleteProviderKeySecrets(provider)
+
+	r.servedMu.Lock()
+	defer r.servedMu.Unlock()
+
+	for key, providers := range r.served {
+		r.served[key] = slices.DeleteFunc(providers, func(name string) bool { return name == provider })
+	}
+
+	delete(r.servedCount, provider)
+	r.providerSecretsGauge.DeleteLabelValues(provider)
 }
 
 // DeleteCondition deletes the condition metrics for the ref.
@@ -163,6 +481,8 @@ func (r *Recorder) DeleteSecret(secret *sopsv1alpha1.SopsSecret) {
 		"name":      secret.Name,
 		"namespace": secret.Namespace,
 	})
+
+	r.forgetServedSecret(secret)
 }
 
 // DeleteCondition deletes the condition metrics for the ref.
@@ -175,6 +495,8 @@ func (r *Recorder) DeleteGlobalSecret(secret *sopsv1alpha1.GlobalSopsSecret) {
 	r.globalSecretConditionGauge.DeletePartialMatch(map[string]string{
 		"name": secret.Name,
 	})
+
+	r.forgetServedSecret(secret)
 }
 
 // DeleteCondition deletes the condition metrics for the ref.
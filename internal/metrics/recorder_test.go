@@ -4,7 +4,9 @@
 package metrics
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
@@ -13,6 +15,7 @@ import (
 	sopsv1alpha1 "github.com/peak-scale/sops-operator/api/v1alpha1"
 	"github.com/peak-scale/sops-operator/internal/meta"
 	capmeta "github.com/projectcapsule/capsule/pkg/api/meta"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -98,3 +101,129 @@ func metricLabels(metric *dto.Metric) map[string]string {
 
 	return labels
 }
+
+func TestRecordDecryptionMetrics(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder()
+	recorder.ObserveDecryption("age", 20*time.Millisecond, "")
+	recorder.ObserveDecryption("none", 5*time.Second, "data_key")
+	recorder.ObserveKeyServiceCall("aws_kms", 2*time.Second, errors.New("timeout"))
+	recorder.RecordDecryptionFailure(DecryptionFailureNoProvider)
+	recorder.RecordSecretOperation(SecretOperationCreate)
+	recorder.RecordSecretOperation(SecretOperationCreate)
+	recorder.RecordSecretOperation(SecretOperationDelete)
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(recorder.Collectors()...)
+	metricFamilies, err := registry.Gather()
+	require.NoError(t, err)
+
+	durations := findMetricFamily(t, metricFamilies, "sops_decryption_duration_seconds")
+	require.Len(t, durations.Metric, 2)
+	require.Equal(t, map[string]string{"key_type": "age"}, metricLabels(durations.Metric[0]))
+	require.Equal(t, uint64(1), durations.Metric[0].GetHistogram().GetSampleCount())
+
+	calls := findMetricFamily(t, metricFamilies, "sops_key_service_request_duration_seconds")
+	require.Len(t, calls.Metric, 1)
+	require.Equal(t, map[string]string{"key_type": "aws_kms", "result": "error"}, metricLabels(calls.Metric[0]))
+
+	failures := findMetricFamily(t, metricFamilies, "sops_decryption_failures_total")
+	require.Len(t, failures.Metric, 2)
+	require.Equal(t, map[string]string{"reason": "data_key"}, metricLabels(failures.Metric[0]))
+	require.Equal(t, map[string]string{"reason": DecryptionFailureNoProvider}, metricLabels(failures.Metric[1]))
+
+	operations := findMetricFamily(t, metricFamilies, "sops_secret_operations_total")
+	require.Len(t, operations.Metric, 2)
+	require.Equal(t, map[string]string{"operation": SecretOperationCreate}, metricLabels(operations.Metric[0]))
+	require.Equal(t, float64(2), operations.Metric[0].GetCounter().GetValue())
+}
+
+func TestRecordProviderKeySecret(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "sops-system"}}
+	expiry := time.Unix(1700000000, 0)
+
+	recorder.RecordProviderKeySecret("provider", secret, KeySecretState{
+		Loaded:           true,
+		Identities:       map[string]int{"age": 2, "pgp": 1},
+		PGPExpiry:        map[string]time.Time{"ABCD": expiry},
+		VaultTokenExpiry: expiry,
+	})
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(recorder.Collectors()...)
+	metricFamilies, err := registry.Gather()
+	require.NoError(t, err)
+
+	loaded := findMetricFamily(t, metricFamilies, "sops_provider_key_secret_loaded")
+	require.Len(t, loaded.Metric, 1)
+	require.Equal(t, map[string]string{
+		"provider":  "provider",
+		"secret":    "keys",
+		"namespace": "sops-system",
+	}, metricLabels(loaded.Metric[0]))
+	require.Equal(t, float64(1), loaded.Metric[0].GetGauge().GetValue())
+
+	identities := findMetricFamily(t, metricFamilies, "sops_provider_key_secret_identities")
+	require.Len(t, identities.Metric, 2)
+	require.Equal(t, float64(2), identities.Metric[0].GetGauge().GetValue())
+
+	pgp := findMetricFamily(t, metricFamilies, "sops_provider_key_secret_pgp_key_expiry_timestamp_seconds")
+	require.Equal(t, float64(1700000000), pgp.Metric[0].GetGauge().GetValue())
+	require.Equal(t, "ABCD", metricLabels(pgp.Metric[0])["fingerprint"])
+
+	vault := findMetricFamily(t, metricFamilies, "sops_provider_key_secret_vault_token_expiry_timestamp_seconds")
+	require.Equal(t, float64(1700000000), vault.Metric[0].GetGauge().GetValue())
+
+	recorder.DeleteProvider(&sopsv1alpha1.SopsProvider{ObjectMeta: metav1.ObjectMeta{Name: "provider"}})
+
+	metricFamilies, err = registry.Gather()
+	require.NoError(t, err)
+	require.Empty(t, metricFamilies)
+}
+
+func TestRecordServedSecret(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder()
+	first := &sopsv1alpha1.SopsSecret{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "tenant"}}
+	second := &sopsv1alpha1.SopsSecret{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "tenant"}}
+	global := &sopsv1alpha1.GlobalSopsSecret{ObjectMeta: metav1.ObjectMeta{Name: "first"}}
+
+	recorder.RecordServedSecret(first, []string{"platform", "tenant"})
+	recorder.RecordServedSecret(second, []string{"tenant"})
+	recorder.RecordServedSecret(global, []string{"platform"})
+
+	served := func() map[string]float64 {
+		registry := prometheus.NewPedanticRegistry()
+		registry.MustRegister(recorder.Collectors()...)
+		metricFamilies, err := registry.Gather()
+		require.NoError(t, err)
+
+		counts := make(map[string]float64)
+		for _, metric := range findMetricFamily(t, metricFamilies, "sops_provider_secrets").Metric {
+			counts[metricLabels(metric)["name"]] = metric.GetGauge().GetValue()
+		}
+
+		return counts
+	}
+
+	require.Equal(t, map[string]float64{"platform": 2, "tenant": 2}, served())
+
+	// Recording the same providers again does not change the counts
+	recorder.RecordServedSecret(first, []string{"tenant", "platform", "tenant"})
+	require.Equal(t, map[string]float64{"platform": 2, "tenant": 2}, served())
+
+	// Provider no longer selects the secret
+	recorder.RecordServedSecret(first, []string{"platform"})
+	require.Equal(t, map[string]float64{"platform": 2, "tenant": 1}, served())
+
+	recorder.DeleteSecret(second)
+	require.Equal(t, map[string]float64{"platform": 2, "tenant": 0}, served())
+
+	recorder.DeleteProvider(&sopsv1alpha1.SopsProvider{ObjectMeta: metav1.ObjectMeta{Name: "tenant"}})
+	require.Equal(t, map[string]float64{"platform": 2}, served())
+}
@@ -0,0 +1,130 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GitDiffStdin reads the changed files from Options.DiffInput instead of
+// running git, for example from the output of git diff --name-only.
+const GitDiffStdin = "-"
+
+// Files changed against the git revision base, relative to workDir. Only added,
+// copied, modified, renamed and type changed files are returned, deleted files
+// can not be checked. Untracked files, which are not part of the diff, are
+// included unless ignored.
+func gitDiffFiles(workDir, base string) ([]string, error) {
+	diff, err := gitFiles(workDir, "diff "+base,
+		"diff", "--name-only", "--relative", "--diff-filter=ACMRT", "-z", base, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := gitFiles(workDir, "ls-files", "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(diff, untracked...), nil
+}
+
+// Runs git in workDir and returns the NUL separated file names it prints.
+func gitFiles(workDir, operation string, args ...string) ([]string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", operation, msg)
+		}
+
+		return nil, fmt.Errorf("git %s: %w", operation, err)
+	}
+
+	files := make([]string, 0)
+
+	for name := range strings.SplitSeq(string(out), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+
+	return files, nil
+}
+
+// Reads a list of files, one per line, such as the output of git diff --name-only.
+func readFileList(r io.Reader) ([]string, error) {
+	if r == nil {
+		return nil, errors.New("no input to read changed files from")
+	}
+
+	files := make([]string, 0)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			files = append(files, name)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read changed files: %w", err)
+	}
+
+	return files, nil
+}
+
+// Resolves the changed files for the diff mode. Files read from input are
+// relative to workDir, like the files returned by git. Files which no longer
+// exist are dropped, as a file list read from stdin may contain deleted files.
+func changedFiles(workDir, base string, input io.Reader) (map[string]struct{}, error) {
+	var (
+		files []string
+		err   error
+	)
+
+	if base == GitDiffStdin {
+		files, err = readFileList(input)
+	} else {
+		files, err = gitDiffFiles(workDir, base)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]struct{}, len(files))
+
+	for _, file := range files {
+		path, err := resolvePath(workDir, file)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("stat %q: %w", file, err)
+		}
+
+		changed[path] = struct{}{}
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunChecksChangedFilesFromInput(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "changed.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "unchanged.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "other/changed.secret.yaml", "password: plain\n")
+
+	result, err := Run(Options{
+		WorkDir:   dir,
+		GitDiff:   GitDiffStdin,
+		DiffInput: strings.NewReader("changed.secret.yaml\ndeleted.secret.yaml\n\nother/changed.secret.yaml\n"),
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	expected := []string{"changed.secret.yaml", "other/changed.secret.yaml"}
+	if !reflect.DeepEqual(result.Checked, expected) {
+		t.Fatalf("expected %v to be checked, got %v", expected, result.Checked)
+	}
+}
+
+func TestRunNarrowsChangedFilesWithGlobs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "changed.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "other/changed.secret.yaml", "password: plain\n")
+
+	result, err := Run(Options{
+		WorkDir:   dir,
+		Globs:     []string{"other/*.yaml"},
+		GitDiff:   GitDiffStdin,
+		DiffInput: strings.NewReader("changed.secret.yaml\nother/changed.secret.yaml\n"),
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	expected := []string{"other/changed.secret.yaml"}
+	if !reflect.DeepEqual(result.Checked, expected) {
+		t.Fatalf("expected %v to be checked, got %v", expected, result.Checked)
+	}
+}
+
+func TestRunChecksGitDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "committed.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "removed.secret.yaml", encryptedYAML())
+
+	git(t, dir, "init", "--quiet")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "--quiet", "-m", "initial")
+	git(t, dir, "rm", "--quiet", "removed.secret.yaml")
+
+	writeFile(t, dir, "modified.secret.yaml", "password: plain\n")
+	git(t, dir, "add", "modified.secret.yaml")
+
+	result, err := Run(Options{
+		WorkDir: dir,
+		GitDiff: "HEAD",
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	expected := []string{"modified.secret.yaml"}
+	if !reflect.DeepEqual(result.Checked, expected) {
+		t.Fatalf("expected %v to be checked, got %v", expected, result.Checked)
+	}
+
+	if _, err := Run(Options{WorkDir: dir, GitDiff: "unknown-revision"}); err == nil {
+		t.Fatal("expected error for unknown revision")
+	}
+}
+
+func TestRunChecksChangedFilesInSubdirectory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	writeFile(t, repo, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, repo, ".gitignore", "ignored.secret.yaml\n")
+	writeFile(t, repo, "clusters/committed.secret.yaml", "password: plain\n")
+	writeFile(t, repo, "root.secret.yaml", "password: plain\n")
+
+	git(t, repo, "init", "--quiet")
+	git(t, repo, "add", ".")
+	git(t, repo, "commit", "--quiet", "-m", "initial")
+
+	writeFile(t, repo, "clusters/untracked.secret.yaml", "password: plain\n")
+	writeFile(t, repo, "clusters/ignored.secret.yaml", "password: plain\n")
+	writeFile(t, repo, "untracked.secret.yaml", "password: plain\n")
+
+	dir := filepath.Join(repo, "clusters")
+
+	result, err := Run(Options{
+		WorkDir: dir,
+		GitDiff: "HEAD",
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	expected := []string{"untracked.secret.yaml"}
+	if !reflect.DeepEqual(result.Checked, expected) {
+		t.Fatalf("expected %v to be checked, got %v", expected, result.Checked)
+	}
+
+	result, err = Run(Options{
+		WorkDir:   dir,
+		GitDiff:   GitDiffStdin,
+		DiffInput: strings.NewReader("committed.secret.yaml\n"),
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	expected = []string{"committed.secret.yaml"}
+	if !reflect.DeepEqual(result.Checked, expected) {
+		t.Fatalf("expected %v to be checked, got %v", expected, result.Checked)
+	}
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+}
@@ -4,17 +4,27 @@
 package sopschecker
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/getsops/sops/v3"
 	"github.com/getsops/sops/v3/cmd/sops/common"
 	"github.com/getsops/sops/v3/config"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+	"sigs.k8s.io/yaml"
 )
 
+var lineExpression = regexp.MustCompile(`\bline (\d+)`)
+
 // Options configures a SOPS encryption check.
 type Options struct {
 	// ConfigPath is an optional path to a .sops.yaml file. When set, files are
@@ -30,17 +40,92 @@ type Options struct {
 	// WorkDir is used to resolve relative file and config paths. It defaults to
 	// the current working directory.
 	WorkDir string
+	// Manifests checks YAML files as Kubernetes manifests, in addition to the
+	// .sops.yaml creation rules. Each document is checked for plain Secrets
+	// with data and for SopsSecrets which are not encrypted.
+	Manifests bool
+	// VerifyRecipients verifies the recipients of encrypted files against the
+	// keys of the matching .sops.yaml creation rule, or against the policy at
+	// PolicyPath when set.
+	VerifyRecipients bool
+	// PolicyPath is an optional path to a recipient policy file.
+	PolicyPath string
+	// GitDiff restricts the check to files added or modified compared to the
+	// given git revision. With GitDiffStdin, the changed files are read from
+	// DiffInput. Files and Globs further narrow down the changed files.
+	GitDiff string
+	// DiffInput contains the changed files, one per line, when GitDiff is
+	// GitDiffStdin.
+	DiffInput io.Reader
+	// IgnoreFile is an optional path to a file with gitignore patterns of files
+	// to exclude. It defaults to .sopscheckerignore in the working directory,
+	// which is skipped when it does not exist.
+	IgnoreFile string
+	// Excludes contains additional gitignore patterns of files to exclude.
+	Excludes []string
+	// VerifyDecrypt decrypts every encrypted file with the keys at KeyPaths,
+	// including the MAC verification.
+	VerifyDecrypt bool
+	// KeyPaths contains key files, directories of key files or Secret manifests,
+	// named like the entries of a SopsProvider decryption Secret.
+	KeyPaths []string
+	// MaxKeyAge reports encrypted files whose data key or master keys are older,
+	// when set.
+	MaxKeyAge time.Duration
+	// RotationCommands adds the sops commands to rotate stale keys to the
+	// failures.
+	RotationCommands bool
 }
 
-// Failure describes a file that should have been encrypted but was not.
+// CheckEncrypted identifies failures of files requiring encryption, which are
+// not encrypted.
+const CheckEncrypted = "sops-encrypted"
+
+// requireAllRule is reported as rule when every file is required to be encrypted.
+const requireAllRule = "--require-all"
+
+// Failure describes a file or document that should have been encrypted but was not.
 type Failure struct {
-	Path   string
-	Reason string
+	// Path of the file, relative to the working directory when possible.
+	Path string `json:"path"`
+	// Check which failed.
+	Check string `json:"check"`
+	// Rule which requires the file to be encrypted, such as the matched
+	// .sops.yaml creation rule.
+	Rule string `json:"rule"`
+	// Reason the check failed.
+	Reason string `json:"reason"`
+	// Line the failure refers to, 0 when unknown.
+	Line int `json:"line,omitempty"`
+	// Document is the index of the YAML document within the file the failure
+	// refers to, starting at 0. Only set for manifest checks.
+	Document *int `json:"document,omitempty"`
+	// Commands which resolve the failure, when requested.
+	Commands []string `json:"commands,omitempty"`
+}
+
+// Result is the structured result of a check.
+type Result struct {
+	// Checked contains every file which was required to be encrypted.
+	Checked []string `json:"checked"`
+	// Failures contains every failed check.
+	Failures []Failure `json:"failures"`
 }
 
 // Check validates that files requiring SOPS encryption are encrypted.
 func Check(opts Options) ([]Failure, error) {
-	if len(opts.Files) == 0 && len(opts.Globs) == 0 {
+	result, err := Run(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Failures, nil
+}
+
+// Run validates that files requiring SOPS encryption are encrypted and returns
+// the structured result.
+func Run(opts Options) (*Result, error) {
+	if len(opts.Files) == 0 && len(opts.Globs) == 0 && opts.GitDiff == "" {
 		return nil, errors.New("no files or globs provided")
 	}
 
@@ -65,10 +150,49 @@ func Check(opts Options) ([]Failure, error) {
 		configPath = resolved
 	}
 
+	var policy *RecipientPolicy
+
+	if opts.PolicyPath != "" {
+		resolved, err := resolvePath(workDir, opts.PolicyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		policy, err = LoadRecipientPolicy(resolved)
+		if err != nil {
+			return nil, err
+		}
+	} else if opts.VerifyRecipients && opts.RequireAll {
+		return nil, errors.New("verifying recipients with --require-all requires a recipient policy")
+	}
+
+	ignorePath, err := resolvePath(workDir, cmp.Or(opts.IgnoreFile, IgnoreFile))
+	if err != nil {
+		return nil, err
+	}
+
+	ignore, err := LoadIgnore(ignorePath, opts.IgnoreFile != "", opts.Excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	var dec *decryptor.SOPSDecryptor
+
+	if opts.VerifyDecrypt {
+		var cleanup func()
+
+		dec, cleanup, err = newDecryptor(workDir, opts.KeyPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		defer cleanup()
+	}
+
 	files := opts.Files
 
 	if len(opts.Globs) > 0 {
-		globbedFiles, err := expandGlobs(workDir, opts.Globs)
+		globbedFiles, err := expandGlobs(workDir, opts.Globs, ignore)
 		if err != nil {
 			return nil, err
 		}
@@ -76,7 +200,22 @@ func Check(opts Options) ([]Failure, error) {
 		files = globbedFiles
 	}
 
-	failures := make([]Failure, 0)
+	if opts.GitDiff != "" {
+		changed, err := changedFiles(workDir, opts.GitDiff, opts.DiffInput)
+		if err != nil {
+			return nil, err
+		}
+
+		files, err = filterChanged(workDir, files, changed, len(opts.Files) == 0 && len(opts.Globs) == 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Result{
+		Checked:  make([]string, 0),
+		Failures: make([]Failure, 0),
+	}
 
 	for _, file := range files {
 		path, err := resolvePath(workDir, file)
@@ -89,75 +228,127 @@ func Check(opts Options) ([]Failure, error) {
 			return nil, fmt.Errorf("stat %q: %w", file, err)
 		}
 
-		if info.IsDir() {
+		if info.IsDir() || ignore.Match(relativeTo(workDir, path), false) {
 			continue
 		}
 
-		required, storesConfig, err := encryptionRequired(path, configPath, opts.RequireAll)
+		// Manifests are checked in addition to the creation rules
+		manifest := opts.Manifests && isManifest(path)
+		if manifest {
+			failures, err := checkManifest(path, displayPath(workDir, path))
+			if err != nil {
+				return nil, err
+			}
+
+			result.Failures = append(result.Failures, failures...)
+		}
+
+		req, err := encryptionRequired(path, configPath, opts.RequireAll)
 		if err != nil {
 			return nil, err
 		}
 
-		if !required {
+		if req == nil {
+			if manifest {
+				result.Checked = append(result.Checked, displayPath(workDir, path))
+			}
+
 			continue
 		}
 
-		encrypted, reason := isEncrypted(path, storesConfig)
-		if !encrypted {
-			failures = append(failures, Failure{
+		result.Checked = append(result.Checked, displayPath(workDir, path))
+
+		tree, reason := loadEncrypted(path, req.Stores)
+		if tree == nil {
+			result.Failures = append(result.Failures, Failure{
 				Path:   displayPath(workDir, path),
+				Check:  CheckEncrypted,
+				Rule:   req.Rule,
 				Reason: reason,
+				Line:   reasonLine(reason),
 			})
+
+			continue
+		}
+
+		if opts.VerifyRecipients || policy != nil {
+			if failure := checkRecipients(displayPath(workDir, path), tree, req, policy); failure != nil {
+				result.Failures = append(result.Failures, *failure)
+			}
+		}
+
+		if opts.MaxKeyAge > 0 {
+			if failure := checkRotation(displayPath(workDir, path), tree, req.Rule, opts.MaxKeyAge, opts.RotationCommands); failure != nil {
+				result.Failures = append(result.Failures, *failure)
+			}
+		}
+
+		if dec != nil {
+			if reason := decryptFile(dec, path); reason != "" {
+				result.Failures = append(result.Failures, Failure{
+					Path:   displayPath(workDir, path),
+					Check:  CheckDecrypt,
+					Rule:   req.Rule,
+					Reason: reason,
+				})
+			}
 		}
 	}
 
-	return failures, nil
+	return result, nil
 }
 
-func expandGlobs(workDir string, patterns []string) ([]string, error) {
-	seen := make(map[string]struct{})
+// Restricts files to the changed files. Without selection, all changed files
+// are returned.
+func filterChanged(workDir string, files []string, changed map[string]struct{}, all bool) ([]string, error) {
+	filtered := make([]string, 0, len(changed))
 
-	for _, pattern := range patterns {
-		resolvedPattern := pattern
-		if !filepath.IsAbs(resolvedPattern) {
-			resolvedPattern = filepath.Join(workDir, resolvedPattern)
+	if all {
+		for path := range changed {
+			filtered = append(filtered, path)
 		}
 
-		matches, err := filepath.Glob(resolvedPattern)
-		if err != nil {
-			return nil, fmt.Errorf("expand glob %q: %w", pattern, err)
-		}
+		sort.Strings(filtered)
 
-		for _, match := range matches {
-			abs, err := filepath.Abs(match)
-			if err != nil {
-				return nil, fmt.Errorf("resolve glob match %q: %w", match, err)
-			}
+		return filtered, nil
+	}
 
-			seen[abs] = struct{}{}
+	for _, file := range files {
+		path, err := resolvePath(workDir, file)
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	files := make([]string, 0, len(seen))
-	for file := range seen {
-		files = append(files, file)
+		if _, ok := changed[path]; ok {
+			filtered = append(filtered, path)
+		}
 	}
 
-	sort.Strings(files)
+	return filtered, nil
+}
 
-	return files, nil
+// requirement describes why a file is required to be encrypted.
+type requirement struct {
+	// Rule which requires the file to be encrypted.
+	Rule string
+	// Creation is the matched creation rule, nil for --require-all.
+	Creation *config.Config
+	// Stores configures how the file is loaded.
+	Stores *config.StoresConfig
 }
 
-func encryptionRequired(filePath, configuredPath string, requireAll bool) (bool, *config.StoresConfig, error) {
+// Returns the requirement for the file to be encrypted, or nil when the file is
+// not required to be encrypted.
+func encryptionRequired(filePath, configuredPath string, requireAll bool) (*requirement, error) {
 	if requireAll {
-		return true, config.NewStoresConfig(), nil
+		return &requirement{Rule: requireAllRule, Stores: config.NewStoresConfig()}, nil
 	}
 
 	configPath := configuredPath
 	if configPath == "" {
 		result, _ := config.LookupConfigFile(filePath)
 		if result.Path == "" {
-			return false, nil, nil
+			return nil, nil
 		}
 
 		configPath = result.Path
@@ -166,35 +357,96 @@ func encryptionRequired(filePath, configuredPath string, requireAll bool) (bool,
 	creationRule, err := config.LoadCreationRuleForFile(configPath, filePath, nil)
 	if err != nil {
 		if isNoMatchingRuleError(err) {
-			return false, nil, nil
+			return nil, nil
 		}
 
-		return false, nil, fmt.Errorf("load creation rule for %q: %w", filePath, err)
+		return nil, fmt.Errorf("load creation rule for %q: %w", filePath, err)
 	}
 
 	if creationRule == nil {
-		return false, nil, nil
+		return nil, nil
 	}
 
 	storesConfig, err := config.LoadStoresConfig(configPath)
 	if err != nil {
-		return false, nil, fmt.Errorf("load stores config %q: %w", configPath, err)
+		return nil, fmt.Errorf("load stores config %q: %w", configPath, err)
+	}
+
+	rule, err := matchingCreationRule(configPath, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &requirement{Rule: rule, Creation: creationRule, Stores: storesConfig}, nil
+}
+
+// Describes the creation rule matching the file, using the same matching as
+// SOPS: the first rule without path_regex or with a matching path_regex wins.
+func matchingCreationRule(configPath, filePath string) (string, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", fmt.Errorf("read config %q: %w", configPath, err)
+	}
+
+	var conf struct {
+		CreationRules []struct {
+			PathRegex string `json:"path_regex"`
+		} `json:"creation_rules"`
+	}
+
+	if err := yaml.Unmarshal(content, &conf); err != nil {
+		return "", fmt.Errorf("parse config %q: %w", configPath, err)
+	}
+
+	configDir, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		return "", fmt.Errorf("resolve %q: %w", configPath, err)
+	}
+
+	relPath := strings.TrimPrefix(filePath, configDir+string(filepath.Separator))
+
+	for i, rule := range conf.CreationRules {
+		if rule.PathRegex == "" {
+			return fmt.Sprintf("creation_rules[%d]", i), nil
+		}
+
+		if match, _ := regexp.MatchString(rule.PathRegex, relPath); match {
+			return fmt.Sprintf("creation_rules[%d] (path_regex: %s)", i, rule.PathRegex), nil
+		}
 	}
 
-	return true, storesConfig, nil
+	return "creation_rules", nil
 }
 
-func isEncrypted(path string, storesConfig *config.StoresConfig) (bool, string) {
+// Loads the encrypted file, returns the reason when it is not encrypted.
+func loadEncrypted(path string, storesConfig *config.StoresConfig) (*sops.Tree, string) {
 	if storesConfig == nil {
 		storesConfig = config.NewStoresConfig()
 	}
 
 	store := common.DefaultStoreForPath(storesConfig, path)
-	if _, err := common.LoadEncryptedFile(store, path); err != nil {
-		return false, err.Error()
+
+	tree, err := common.LoadEncryptedFile(store, path)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	return tree, ""
+}
+
+// Line reported by a parser error, 0 when the reason does not mention a line.
+func reasonLine(reason string) int {
+	match := lineExpression.FindStringSubmatch(reason)
+	if match == nil {
+		return 0
+	}
+
+	line, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
 	}
 
-	return true, ""
+	return line
 }
 
 func isNoMatchingRuleError(err error) bool {
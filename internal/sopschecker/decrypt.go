@@ -0,0 +1,66 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/go-logr/logr"
+	"github.com/peak-scale/sops-operator/internal/decryptor"
+)
+
+// CheckDecrypt identifies encrypted files which can not be decrypted with the
+// provided keys or fail the MAC verification.
+const CheckDecrypt = "sops-decrypt"
+
+// Creates a decryptor with the keys at the given paths. A path may be a key
+// file named like the entries of a decryption Secret (*.agekey, *.asc,
+// sops.vault-token, ...), a directory of such files or a Secret manifest.
+func newDecryptor(workDir string, paths []string) (*decryptor.SOPSDecryptor, func(), error) {
+	if len(paths) == 0 {
+		return nil, nil, errors.New("verifying decryption requires keys")
+	}
+
+	dec, cleanup, err := decryptor.NewSOPSTempDecryptor()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dec.SetCheckSopsMac(true)
+
+	for _, path := range paths {
+		resolved, err := resolvePath(workDir, path)
+		if err != nil {
+			cleanup()
+
+			return nil, nil, err
+		}
+
+		if err := dec.KeysFromPath(resolved); err != nil {
+			cleanup()
+
+			return nil, nil, err
+		}
+	}
+
+	return dec, cleanup, nil
+}
+
+// Decrypts the file, returns the reason when decryption fails.
+func decryptFile(dec *decryptor.SOPSDecryptor, path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err.Error()
+	}
+
+	format := formats.FormatForPath(path)
+	if _, err := dec.SopsDecryptWithFormat(context.Background(), content, logr.Discard(), format, format); err != nil {
+		return err.Error()
+	}
+
+	return ""
+}
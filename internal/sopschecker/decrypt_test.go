@@ -0,0 +1,127 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRunVerifiesDecryption(t *testing.T) {
+	key, err := os.ReadFile("testdata/age.agekey")
+	if err != nil {
+		t.Fatalf("read key: %v", err)
+	}
+
+	encrypted, err := os.ReadFile("testdata/app.secret.env")
+	if err != nil {
+		t.Fatalf("read encrypted file: %v", err)
+	}
+
+	// Removing a value keeps the file decryptable, but breaks the MAC
+	tampered := make([]string, 0)
+	for line := range strings.Lines(string(encrypted)) {
+		if !strings.HasPrefix(line, "DATABASE_USER=") {
+			tampered = append(tampered, line)
+		}
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.secret.env", string(encrypted))
+	writeFile(t, dir, "tampered.secret.env", strings.Join(tampered, ""))
+	writeFile(t, dir, "keys/age.agekey", string(key))
+	writeFile(t, dir, "keys/ignored.txt", "not a key")
+	writeFile(t, dir, "provider.yaml", `apiVersion: v1
+kind: Secret
+metadata:
+  name: keys
+data:
+  age.agekey: `+base64.StdEncoding.EncodeToString(key)+`
+`)
+
+	tests := []struct {
+		name     string
+		keys     []string
+		failures map[string]string
+	}{
+		{
+			name: "key file",
+			keys: []string{"keys/age.agekey"},
+			failures: map[string]string{
+				"tampered.secret.env": "failed to verify sops data integrity",
+			},
+		},
+		{
+			name: "directory",
+			keys: []string{"keys"},
+			failures: map[string]string{
+				"tampered.secret.env": "failed to verify sops data integrity",
+			},
+		},
+		{
+			name: "secret manifest",
+			keys: []string{"provider.yaml"},
+			failures: map[string]string{
+				"tampered.secret.env": "failed to verify sops data integrity",
+			},
+		},
+		{
+			name: "missing key",
+			keys: []string{"keys/ignored.txt"},
+			failures: map[string]string{
+				"app.secret.env":      "cannot get sops data key",
+				"tampered.secret.env": "cannot get sops data key",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(Options{
+				WorkDir:       dir,
+				Globs:         []string{"*.secret.env"},
+				RequireAll:    true,
+				VerifyDecrypt: true,
+				KeyPaths:      tt.keys,
+			})
+			if err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+
+			if len(result.Failures) != len(tt.failures) {
+				t.Fatalf("expected %d failures, got %#v", len(tt.failures), result.Failures)
+			}
+
+			for _, failure := range result.Failures {
+				if failure.Check != CheckDecrypt {
+					t.Fatalf("unexpected check: %s", failure.Check)
+				}
+
+				if !strings.Contains(failure.Reason, tt.failures[failure.Path]) {
+					t.Fatalf("unexpected reason for %s: %s", failure.Path, failure.Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestRunVerifyDecryptRequiresKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.secret.yaml", encryptedYAML())
+	writeFile(t, dir, "configmap.yaml", "apiVersion: v1\nkind: ConfigMap\n")
+
+	for _, keys := range [][]string{nil, {"missing.agekey"}, {"configmap.yaml"}} {
+		if _, err := Run(Options{
+			WorkDir:       dir,
+			Files:         []string{"app.secret.yaml"},
+			RequireAll:    true,
+			VerifyDecrypt: true,
+			KeyPaths:      keys,
+		}); err == nil {
+			t.Fatalf("expected error for keys %v", keys)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// globStar matches zero or more path segments.
+const globStar = "**"
+
+// Expands the glob patterns relative to workDir. Besides the filepath.Match
+// syntax, a "**" segment matches any number of directories. Directories
+// excluded by the ignore rules and .git directories are not traversed.
+func expandGlobs(workDir string, patterns []string, ignore *Ignore) ([]string, error) {
+	seen := make(map[string]struct{})
+
+	for _, pattern := range patterns {
+		resolvedPattern := pattern
+		if !filepath.IsAbs(resolvedPattern) {
+			resolvedPattern = filepath.Join(workDir, resolvedPattern)
+		}
+
+		segments := strings.Split(filepath.ToSlash(resolvedPattern), "/")
+		if err := validateGlob(segments); err != nil {
+			return nil, fmt.Errorf("expand glob %q: %w", pattern, err)
+		}
+
+		// Walk from the longest prefix without wildcards
+		static := 0
+		for static < len(segments) && !hasMeta(segments[static]) {
+			static++
+		}
+
+		root := filepath.FromSlash(strings.Join(segments[:static], "/"))
+		if root == "" {
+			root = string(filepath.Separator)
+		}
+
+		if static == len(segments) {
+			if _, err := os.Lstat(root); err == nil {
+				seen[root] = struct{}{}
+			}
+
+			continue
+		}
+
+		matches, err := walkGlob(workDir, root, segments[static:], ignore)
+		if err != nil {
+			return nil, fmt.Errorf("expand glob %q: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			seen[match] = struct{}{}
+		}
+	}
+
+	files := make([]string, 0, len(seen))
+	for file := range seen {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+func walkGlob(workDir, root string, pattern []string, ignore *Ignore) ([]string, error) {
+	matches := make([]string, 0)
+
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if file == root && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipAll
+			}
+
+			return err
+		}
+
+		if file == root {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+
+		segments := strings.Split(filepath.ToSlash(rel), "/")
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" || ignore.Match(relativeTo(workDir, file), true) {
+				return filepath.SkipDir
+			}
+
+			if matchSegments(pattern, segments) {
+				matches = append(matches, file)
+			}
+
+			if !matchPrefix(pattern, segments) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if matchSegments(pattern, segments) {
+			matches = append(matches, file)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// Reports whether the path segments match the pattern segments.
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == globStar {
+		// A trailing "**" matches everything below, but not the directory itself
+		if len(pattern) == 1 {
+			return len(name) > 0
+		}
+
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], name[1:])
+}
+
+// Reports whether paths below the directory segments may match the pattern.
+func matchPrefix(pattern, dir []string) bool {
+	for i, segment := range dir {
+		if i >= len(pattern) {
+			return false
+		}
+
+		if pattern[i] == globStar {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern[i], segment); !ok {
+			return false
+		}
+	}
+
+	return len(dir) < len(pattern)
+}
+
+func validateGlob(segments []string) error {
+	for _, segment := range segments {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasMeta(segment string) bool {
+	return strings.ContainsAny(segment, `*?[\`)
+}
+
+// Path relative to workDir with forward slashes, empty when outside of workDir.
+func relativeTo(workDir, file string) string {
+	rel, err := filepath.Rel(workDir, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,87 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{pattern: "*.yaml", name: "app.yaml", match: true},
+		{pattern: "*.yaml", name: "secrets/app.yaml", match: false},
+		{pattern: "**/*.yaml", name: "app.yaml", match: true},
+		{pattern: "**/*.yaml", name: "clusters/dev/app.yaml", match: true},
+		{pattern: "clusters/**/secret.yaml", name: "clusters/secret.yaml", match: true},
+		{pattern: "clusters/**/secret.yaml", name: "clusters/dev/apps/secret.yaml", match: true},
+		{pattern: "clusters/**/secret.yaml", name: "other/dev/secret.yaml", match: false},
+		{pattern: "clusters/**", name: "clusters", match: false},
+		{pattern: "clusters/**", name: "clusters/dev/app.yaml", match: true},
+		{pattern: "clusters/*/app.yaml", name: "clusters/dev/prod/app.yaml", match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := matchSegments(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/")); got != tt.match {
+				t.Fatalf("expected match %t, got %t", tt.match, got)
+			}
+		})
+	}
+}
+
+func TestRunExpandsRecursiveGlobs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "app.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "clusters/dev/app.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "clusters/dev/apps/app.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "clusters/dev/app.yaml", "password: plain\n")
+	writeFile(t, dir, ".git/app.secret.yaml", "password: plain\n")
+
+	tests := []struct {
+		glob     string
+		expected []string
+	}{
+		{
+			glob:     "**/*.secret.yaml",
+			expected: []string{"app.secret.yaml", "clusters/dev/app.secret.yaml", "clusters/dev/apps/app.secret.yaml"},
+		},
+		{
+			glob:     "clusters/**/*.secret.yaml",
+			expected: []string{"clusters/dev/app.secret.yaml", "clusters/dev/apps/app.secret.yaml"},
+		},
+		{
+			glob:     "clusters/*/*.secret.yaml",
+			expected: []string{"clusters/dev/app.secret.yaml"},
+		},
+		{
+			glob:     "missing/**/*.yaml",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob, func(t *testing.T) {
+			result, err := Run(Options{
+				WorkDir: dir,
+				Globs:   []string{tt.glob},
+			})
+			if err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Checked, tt.expected) {
+				t.Fatalf("expected %v to be checked, got %v", tt.expected, result.Checked)
+			}
+		})
+	}
+}
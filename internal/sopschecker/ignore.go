@@ -0,0 +1,156 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// IgnoreFile is the default ignore file, read from the working directory.
+const IgnoreFile = ".sopscheckerignore"
+
+// Ignore excludes files from checks, using gitignore semantics.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// LoadIgnore reads the ignore file at path and appends the exclude patterns.
+// A missing file is not an error, unless required is set.
+func LoadIgnore(path string, required bool, excludes []string) (*Ignore, error) {
+	ignore := &Ignore{}
+
+	file, err := os.Open(path)
+
+	switch {
+	case err == nil:
+		defer file.Close()
+
+		if err := ignore.Parse(file); err != nil {
+			return nil, fmt.Errorf("read ignore file %q: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !required:
+	default:
+		return nil, fmt.Errorf("open ignore file %q: %w", path, err)
+	}
+
+	for _, exclude := range excludes {
+		if err := ignore.Add(exclude); err != nil {
+			return nil, fmt.Errorf("exclude %q: %w", exclude, err)
+		}
+	}
+
+	return ignore, nil
+}
+
+// Parse adds the patterns read from r, one per line.
+func (i *Ignore) Parse(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if err := i.Add(scanner.Text()); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Add a single gitignore pattern. Blank lines and comments are skipped.
+func (i *Ignore) Add(pattern string) error {
+	pattern = trimTrailingSpaces(pattern)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return nil
+	}
+
+	rule := ignoreRule{}
+
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		rule.negate = true
+		pattern = pattern[1:]
+	case strings.HasPrefix(pattern, `\!`), strings.HasPrefix(pattern, `\#`):
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	if pattern == "" {
+		return nil
+	}
+
+	// Patterns without a separator match at any level, others are anchored
+	anchored := strings.Contains(pattern, "/")
+
+	rule.segments = strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	if !anchored {
+		rule.segments = append([]string{globStar}, rule.segments...)
+	}
+
+	if err := validateGlob(rule.segments); err != nil {
+		return err
+	}
+
+	i.rules = append(i.rules, rule)
+
+	return nil
+}
+
+// Match reports whether the slash separated path, relative to the working
+// directory, is ignored. As with git, files can not be re-included when a
+// parent directory is ignored.
+func (i *Ignore) Match(rel string, isDir bool) bool {
+	if i == nil || len(i.rules) == 0 || rel == "" {
+		return false
+	}
+
+	segments := strings.Split(rel, "/")
+
+	for n := 1; n < len(segments); n++ {
+		if i.match(segments[:n], true) {
+			return true
+		}
+	}
+
+	return i.match(segments, isDir)
+}
+
+// The last matching rule wins.
+func (i *Ignore) match(segments []string, isDir bool) bool {
+	ignored := false
+
+	for _, rule := range i.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		if matchSegments(rule.segments, segments) {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// Trailing spaces are removed, unless escaped with a backslash.
+func trimTrailingSpaces(pattern string) string {
+	trimmed := strings.TrimRight(pattern, " \t\r")
+	if strings.HasSuffix(trimmed, `\`) && len(trimmed) < len(pattern) {
+		return trimmed[:len(trimmed)-1] + " "
+	}
+
+	return trimmed
+}
@@ -0,0 +1,118 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	ignore := &Ignore{}
+	if err := ignore.Parse(strings.NewReader(`# comment
+
+*.example.yaml
+!keep.example.yaml
+/root.yaml
+vendor/
+testdata/**
+clusters/*/generated.yaml
+\#hash.yaml
+`)); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{path: "app.example.yaml", ignored: true},
+		{path: "secrets/app.example.yaml", ignored: true},
+		{path: "secrets/keep.example.yaml", ignored: false},
+		{path: "root.yaml", ignored: true},
+		{path: "secrets/root.yaml", ignored: false},
+		{path: "vendor", isDir: true, ignored: true},
+		{path: "vendor", ignored: false},
+		{path: "vendor/app.yaml", ignored: true},
+		{path: "secrets/vendor/app.yaml", ignored: true},
+		{path: "testdata", isDir: true, ignored: false},
+		{path: "testdata/nested/app.yaml", ignored: true},
+		{path: "clusters/dev/generated.yaml", ignored: true},
+		{path: "clusters/dev/apps/generated.yaml", ignored: false},
+		{path: "#hash.yaml", ignored: true},
+		{path: "app.yaml", ignored: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ignore.Match(tt.path, tt.isDir); got != tt.ignored {
+				t.Fatalf("expected ignored %t, got %t", tt.ignored, got)
+			}
+		})
+	}
+}
+
+func TestIgnoreCannotReincludeBelowIgnoredDirectory(t *testing.T) {
+	ignore := &Ignore{}
+	if err := ignore.Parse(strings.NewReader("build/\n!build/app.yaml\n")); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if !ignore.Match("build/app.yaml", false) {
+		t.Fatal("expected build/app.yaml to be ignored")
+	}
+}
+
+func TestIgnoreRejectsInvalidPattern(t *testing.T) {
+	if err := (&Ignore{}).Add("["); err == nil {
+		t.Fatal("expected error for invalid pattern")
+	}
+}
+
+func TestRunExcludesIgnoredFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, IgnoreFile, "examples/\n")
+	writeFile(t, dir, "app.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "test.secret.yaml", "password: plain\n")
+	writeFile(t, dir, "examples/app.secret.yaml", "password: plain\n")
+
+	result, err := Run(Options{
+		WorkDir:  dir,
+		Globs:    []string{"**/*.secret.yaml"},
+		Excludes: []string{"test.*"},
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	expected := []string{"app.secret.yaml"}
+	if !reflect.DeepEqual(result.Checked, expected) {
+		t.Fatalf("expected %v to be checked, got %v", expected, result.Checked)
+	}
+
+	result, err = Run(Options{
+		WorkDir: dir,
+		Files:   []string{"examples/app.secret.yaml"},
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Checked) != 0 {
+		t.Fatalf("expected ignored file not to be checked, got %v", result.Checked)
+	}
+
+	if _, err := Run(Options{
+		WorkDir:    dir,
+		Files:      []string{"app.secret.yaml"},
+		IgnoreFile: "missing",
+	}); err == nil {
+		t.Fatal("expected error for missing ignore file")
+	}
+}
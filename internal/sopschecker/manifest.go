@@ -0,0 +1,174 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// CheckPlainSecret identifies plain Kubernetes Secrets containing data.
+	CheckPlainSecret = "plain-secret"
+	// CheckSopsMetadata identifies SopsSecrets without SOPS metadata.
+	CheckSopsMetadata = "sopssecret-metadata"
+	// CheckCleartextValue identifies SopsSecrets with values which are not encrypted.
+	CheckCleartextValue = "sopssecret-cleartext"
+	// CheckManifestSyntax identifies manifests which can not be parsed.
+	CheckManifestSyntax = "manifest-syntax"
+)
+
+// manifestRule is reported as rule for failures of the manifest checks.
+const manifestRule = "--manifests"
+
+const sopsSecretGroup = "addons.projectcapsule.dev/"
+
+// isManifest returns true for files which are checked as Kubernetes manifests.
+func isManifest(path string) bool {
+	return strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+}
+
+// checkManifest checks each document of a multi-document YAML file:
+//   - Plain v1/Secrets must not contain data, v1/Secrets with SOPS metadata
+//     must only contain encrypted values
+//   - SopsSecrets and GlobalSopsSecrets must contain SOPS metadata and only
+//     encrypted data and stringData values
+func checkManifest(path, displayed string) ([]Failure, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %q: %w", path, err)
+	}
+
+	failures := make([]Failure, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+
+	for index := 0; ; index++ {
+		var document yaml.Node
+
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			failures = append(failures, manifestFailure(displayed, index, CheckManifestSyntax, err.Error(), reasonLine(err.Error())))
+
+			break
+		}
+
+		if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+
+		root := document.Content[0]
+		apiVersion := scalarValue(mappingValue(root, "apiVersion"))
+		kind := scalarValue(mappingValue(root, "kind"))
+		name := scalarValue(mappingValue(mappingValue(root, "metadata"), "name"))
+
+		switch {
+		case apiVersion == "v1" && kind == "Secret" && mappingValue(root, "sops") != nil:
+			// Secrets encrypted with sops, e.g. for Flux, are checked like SopsSecrets
+			for _, field := range []string{"data", "stringData"} {
+				for _, key := range cleartextKeys(mappingValue(root, field)) {
+					failures = append(failures, manifestFailure(displayed, index, CheckCleartextValue,
+						fmt.Sprintf("%s %q has cleartext value at %s.%s", kind, name, field, key.Value), key.Line))
+				}
+			}
+		case apiVersion == "v1" && kind == "Secret":
+			for _, field := range []string{"data", "stringData"} {
+				if values := mappingValue(root, field); values != nil && len(values.Content) > 0 {
+					failures = append(failures, manifestFailure(displayed, index, CheckPlainSecret,
+						fmt.Sprintf("plain Secret %q contains %s", name, field), values.Line))
+				}
+			}
+		case strings.HasPrefix(apiVersion, sopsSecretGroup) && (kind == "SopsSecret" || kind == "GlobalSopsSecret"):
+			if mappingValue(root, "sops") == nil {
+				failures = append(failures, manifestFailure(displayed, index, CheckSopsMetadata,
+					fmt.Sprintf("%s %q has no sops metadata", kind, name), root.Line))
+			}
+
+			failures = append(failures, cleartextValues(displayed, index, kind, name, root)...)
+		}
+	}
+
+	return failures, nil
+}
+
+// Report data and stringData values of SopsSecret items which are not encrypted.
+func cleartextValues(displayed string, index int, kind, name string, root *yaml.Node) []Failure {
+	failures := make([]Failure, 0)
+
+	secrets := mappingValue(mappingValue(root, "spec"), "secrets")
+	if secrets == nil || secrets.Kind != yaml.SequenceNode {
+		return failures
+	}
+
+	for i, item := range secrets.Content {
+		for _, field := range []string{"data", "stringData"} {
+			for _, key := range cleartextKeys(mappingValue(item, field)) {
+				failures = append(failures, manifestFailure(displayed, index, CheckCleartextValue,
+					fmt.Sprintf("%s %q has cleartext value at spec.secrets[%d].%s.%s", kind, name, i, field, key.Value), key.Line))
+			}
+		}
+	}
+
+	return failures
+}
+
+// Keys of a mapping node whose values are not encrypted.
+func cleartextKeys(values *yaml.Node) []*yaml.Node {
+	if values == nil || values.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	keys := make([]*yaml.Node, 0)
+
+	for j := 0; j+1 < len(values.Content); j += 2 {
+		if !strings.HasPrefix(values.Content[j+1].Value, "ENC[") {
+			keys = append(keys, values.Content[j])
+		}
+	}
+
+	return keys
+}
+
+func manifestFailure(path string, index int, check, reason string, line int) Failure {
+	return Failure{
+		Path:     path,
+		Check:    check,
+		Rule:     manifestRule,
+		Reason:   reason,
+		Line:     line,
+		Document: &index,
+	}
+}
+
+// Value of a key in a mapping node, nil when absent.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+
+	return node.Value
+}
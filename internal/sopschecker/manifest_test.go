@@ -0,0 +1,199 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"testing"
+)
+
+func TestRunChecksManifestDocuments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "manifests.yaml", `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: plain
+stringData:
+  password: secret
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: empty
+---
+apiVersion: addons.projectcapsule.dev/v1alpha1
+kind: SopsSecret
+metadata:
+  name: unencrypted
+spec:
+  secrets:
+    - name: app
+      stringData:
+        password: secret
+        token: ENC[AES256_GCM,data:abcd,iv:abcd,tag:abcd,type:str]
+---
+apiVersion: addons.projectcapsule.dev/v1alpha1
+kind: GlobalSopsSecret
+metadata:
+  name: encrypted
+spec:
+  secrets:
+    - name: app
+      namespace: default
+      data:
+        password: ENC[AES256_GCM,data:abcd,iv:abcd,tag:abcd,type:str]
+sops:
+  version: 3.8.1
+`)
+
+	result, err := Run(Options{
+		WorkDir:   dir,
+		Files:     []string{"manifests.yaml"},
+		Manifests: true,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Checked) != 1 {
+		t.Fatalf("expected one checked file, got %d", len(result.Checked))
+	}
+
+	want := []struct {
+		check    string
+		document int
+		line     int
+	}{
+		{CheckPlainSecret, 1, 11},
+		{CheckSopsMetadata, 3, 18},
+		{CheckCleartextValue, 3, 26},
+	}
+
+	if len(result.Failures) != len(want) {
+		t.Fatalf("expected %d failures, got %d: %+v", len(want), len(result.Failures), result.Failures)
+	}
+
+	for i, w := range want {
+		failure := result.Failures[i]
+		if failure.Check != w.check || failure.Document == nil || *failure.Document != w.document || failure.Line != w.line {
+			t.Fatalf("unexpected failure %d: %+v (document %v)", i, failure, failure.Document)
+		}
+		if failure.Rule != manifestRule {
+			t.Fatalf("unexpected rule: %s", failure.Rule)
+		}
+	}
+}
+
+func TestRunChecksSopsEncryptedSecrets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "flux.yaml", `apiVersion: v1
+kind: Secret
+metadata:
+  name: flux
+  namespace: flux-system
+type: Opaque
+data:
+  password: ENC[AES256_GCM,data:abcd,iv:abcd,tag:abcd,type:str]
+stringData:
+  token: ENC[AES256_GCM,data:abcd,iv:abcd,tag:abcd,type:str]
+sops:
+  age:
+    - recipient: age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p
+  encrypted_regex: ^(data|stringData)$
+  version: 3.8.1
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: partial
+data:
+  password: ENC[AES256_GCM,data:abcd,iv:abcd,tag:abcd,type:str]
+  token: c2VjcmV0
+sops:
+  version: 3.8.1
+`)
+
+	result, err := Run(Options{
+		WorkDir:   dir,
+		Files:     []string{"flux.yaml"},
+		Manifests: true,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Failures) != 1 {
+		t.Fatalf("expected one failure, got %d: %+v", len(result.Failures), result.Failures)
+	}
+
+	failure := result.Failures[0]
+	if failure.Check != CheckCleartextValue || failure.Document == nil || *failure.Document != 1 || failure.Line != 23 {
+		t.Fatalf("unexpected failure: %+v (document %v)", failure, failure.Document)
+	}
+	if failure.Reason != `Secret "partial" has cleartext value at data.token` {
+		t.Fatalf("unexpected reason: %s", failure.Reason)
+	}
+}
+
+func TestRunReportsInvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "invalid.yaml", "kind: [Secret\n")
+
+	result, err := Run(Options{
+		WorkDir:   dir,
+		Files:     []string{"invalid.yaml"},
+		Manifests: true,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Check != CheckManifestSyntax {
+		t.Fatalf("unexpected failures: %+v", result.Failures)
+	}
+}
+
+func TestRunChecksNonManifestFilesWithCreationRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.env$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "app.env", "PASSWORD=secret\n")
+
+	failures, err := Check(Options{
+		WorkDir:   dir,
+		Files:     []string{"app.env"},
+		Manifests: true,
+	})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if len(failures) != 1 || failures[0].Check != CheckEncrypted {
+		t.Fatalf("unexpected failures: %+v", failures)
+	}
+}
+
+func TestRunChecksManifestsWithCreationRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: secrets/.*\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "secrets/values.yaml", "password: secret\n")
+
+	// Manifest checks do not replace the creation rules
+	failures, err := Check(Options{
+		WorkDir:   dir,
+		Files:     []string{"secrets/values.yaml"},
+		Manifests: true,
+	})
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if len(failures) != 1 || failures[0].Check != CheckEncrypted {
+		t.Fatalf("unexpected failures: %+v", failures)
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// OutputFormat selects how a result is written.
+type OutputFormat string
+
+const (
+	// OutputText writes a human readable list of failures.
+	OutputText OutputFormat = "text"
+	// OutputJSON writes the result as JSON.
+	OutputJSON OutputFormat = "json"
+	// OutputSARIF writes the failures as SARIF 2.1.0 log, e.g. for GitHub code scanning.
+	OutputSARIF OutputFormat = "sarif"
+	// OutputJUnit writes every checked file as JUnit test case, e.g. for GitLab test reports.
+	OutputJUnit OutputFormat = "junit"
+)
+
+const (
+	toolName = "sops-checker"
+	toolURI  = "https://github.com/peak-scale/sops-operator"
+)
+
+// ParseOutputFormat validates an output format.
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(format)); f {
+	case OutputText, OutputJSON, OutputSARIF, OutputJUnit:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q (supported: text, json, sarif, junit)", format)
+	}
+}
+
+// Write writes the result in the given format.
+func (r *Result) Write(w io.Writer, format OutputFormat) error {
+	switch format {
+	case OutputText:
+		return r.writeText(w)
+	case OutputJSON:
+		return writeJSON(w, r)
+	case OutputSARIF:
+		return writeJSON(w, r.sarif())
+	case OutputJUnit:
+		return r.writeJUnit(w)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+func (r *Result) writeText(w io.Writer) error {
+	if len(r.Failures) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "sops-checker: %s:\n", r.textHeader()); err != nil {
+		return err
+	}
+
+	for _, failure := range r.Failures {
+		path := failure.Path
+		if failure.Document != nil {
+			path = fmt.Sprintf("%s (document %d)", path, *failure.Document)
+		}
+
+		if _, err := fmt.Fprintf(w, "  - %s: %s\n", path, failure.Reason); err != nil {
+			return err
+		}
+
+		for _, command := range failure.Commands {
+			if _, err := fmt.Fprintf(w, "      $ %s\n", command); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Header of the text output, describing the failed checks. Failures of
+// different kinds are reported with a neutral header.
+func (r *Result) textHeader() string {
+	header := ""
+
+	for _, failure := range r.Failures {
+		h, ok := textHeaders[failure.Check]
+		if !ok || (header != "" && header != h) {
+			return "files failed checks"
+		}
+
+		header = h
+	}
+
+	return header
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// Headers of the text output, by check.
+var textHeaders = map[string]string{
+	CheckEncrypted:      "files requiring SOPS encryption are not encrypted",
+	CheckPlainSecret:    "files requiring SOPS encryption are not encrypted",
+	CheckSopsMetadata:   "files requiring SOPS encryption are not encrypted",
+	CheckCleartextValue: "files requiring SOPS encryption are not encrypted",
+	CheckManifestSyntax: "manifests are not valid YAML",
+	CheckRecipients:     "encrypted files have unexpected recipients",
+	CheckDecrypt:        "encrypted files can not be decrypted",
+	CheckRotation:       "encrypted files require key rotation",
+}
+
+// Descriptions of the checks, reported as SARIF rules.
+var checkDescriptions = map[string]string{
+	CheckEncrypted:      "Files matching a SOPS creation rule must be encrypted",
+	CheckPlainSecret:    "Kubernetes Secrets must not contain plain data",
+	CheckSopsMetadata:   "SopsSecrets must contain SOPS metadata",
+	CheckCleartextValue: "SopsSecret data and stringData values must be encrypted",
+	CheckManifestSyntax: "Manifests must be valid YAML",
+	CheckRecipients:     "Encrypted files must only be encrypted for the expected recipients",
+	CheckDecrypt:        "Encrypted files must be decryptable with the provided keys",
+	CheckRotation:       "Data keys and master keys must be rotated within the maximum age",
+}
+
+func (r *Result) sarif() sarifLog {
+	rules := make([]sarifRule, 0)
+	seen := make(map[string]struct{})
+	results := make([]sarifResult, 0, len(r.Failures))
+
+	for _, failure := range r.Failures {
+		if _, ok := seen[failure.Check]; !ok {
+			seen[failure.Check] = struct{}{}
+			rules = append(rules, sarifRule{
+				ID:               failure.Check,
+				ShortDescription: sarifMessage{Text: checkDescriptions[failure.Check]},
+			})
+		}
+
+		location := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(failure.Path)},
+		}
+		if failure.Line > 0 {
+			location.Region = &sarifRegion{StartLine: failure.Line}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    failure.Check,
+			Level:     "error",
+			Message:   sarifMessage{Text: failure.message()},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           toolName,
+				InformationURI: toolURI,
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	File      string         `xml:"file,attr"`
+	Failures  []junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (r *Result) writeJUnit(w io.Writer) error {
+	failures := make(map[string][]Failure)
+	for _, failure := range r.Failures {
+		failures[failure.Path] = append(failures[failure.Path], failure)
+	}
+
+	suite := junitSuite{
+		Name:      toolName,
+		Tests:     len(r.Checked),
+		Failures:  len(failures),
+		TestCases: make([]junitTestCase, 0, len(r.Checked)),
+	}
+
+	for _, path := range r.Checked {
+		testCase := junitTestCase{
+			Name:      path,
+			ClassName: toolName,
+			File:      filepath.ToSlash(path),
+		}
+
+		for _, failure := range failures[path] {
+			testCase.Failures = append(testCase.Failures, junitFailure{
+				Message: failure.Reason,
+				Type:    failure.Check,
+				Text:    failure.message(),
+			})
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitSuite{suite},
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// Message describing the failure, including the rule which requires the check.
+func (f Failure) message() string {
+	if f.Document != nil {
+		return fmt.Sprintf("document %d: %s (rule: %s)", *f.Document, f.Reason, f.Rule)
+	}
+
+	return fmt.Sprintf("%s (rule: %s)", f.Reason, f.Rule)
+}
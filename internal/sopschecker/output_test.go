@@ -0,0 +1,176 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunReportsMatchedRuleAndLine(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.env$
+    age: `+testAgeRecipient+`
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "app.secret.yaml", "kind: [Secret\n")
+
+	result, err := Run(Options{
+		WorkDir: dir,
+		Files:   []string{"app.secret.yaml"},
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Checked) != 1 || len(result.Failures) != 1 {
+		t.Fatalf("expected one checked file and one failure, got %d and %d", len(result.Checked), len(result.Failures))
+	}
+
+	failure := result.Failures[0]
+	if failure.Check != CheckEncrypted {
+		t.Fatalf("unexpected check: %s", failure.Check)
+	}
+	if failure.Rule != `creation_rules[1] (path_regex: \.secret\.yaml$)` {
+		t.Fatalf("unexpected rule: %s", failure.Rule)
+	}
+	if failure.Line != 1 {
+		t.Fatalf("expected line 1, got %d (reason: %s)", failure.Line, failure.Reason)
+	}
+}
+
+func TestResultWriteFormats(t *testing.T) {
+	result := &Result{
+		Checked: []string{"secrets/app.yaml", "secrets/db.yaml"},
+		Failures: []Failure{{
+			Path:   "secrets/app.yaml",
+			Check:  CheckEncrypted,
+			Rule:   requireAllRule,
+			Reason: "sops metadata not found",
+			Line:   3,
+		}},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := result.Write(&out, OutputJSON); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+
+		var decoded Result
+		if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid json: %v", err)
+		}
+		if len(decoded.Failures) != 1 || !reflect.DeepEqual(decoded.Failures[0], result.Failures[0]) {
+			t.Fatalf("unexpected failures: %+v", decoded.Failures)
+		}
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := result.Write(&out, OutputSARIF); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+
+		var decoded sarifLog
+		if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid sarif: %v", err)
+		}
+
+		results := decoded.Runs[0].Results
+		if len(results) != 1 || results[0].RuleID != CheckEncrypted {
+			t.Fatalf("unexpected results: %+v", results)
+		}
+
+		location := results[0].Locations[0].PhysicalLocation
+		if location.ArtifactLocation.URI != "secrets/app.yaml" || location.Region == nil || location.Region.StartLine != 3 {
+			t.Fatalf("unexpected location: %+v", location)
+		}
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := result.Write(&out, OutputJUnit); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+
+		var decoded junitTestSuites
+		if err := xml.Unmarshal(out.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid junit: %v", err)
+		}
+		if decoded.Tests != 2 || decoded.Failures != 1 {
+			t.Fatalf("unexpected totals: %d tests, %d failures", decoded.Tests, decoded.Failures)
+		}
+
+		cases := decoded.Suites[0].TestCases
+		if len(cases[0].Failures) != 1 || len(cases[1].Failures) != 0 {
+			t.Fatalf("unexpected test cases: %+v", cases)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := result.Write(&out, OutputText); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+		if !strings.Contains(out.String(), "  - secrets/app.yaml: sops metadata not found") {
+			t.Fatalf("unexpected text output: %s", out.String())
+		}
+	})
+}
+
+func TestTextHeader(t *testing.T) {
+	tests := map[string]struct {
+		checks []string
+		want   string
+	}{
+		"encryption": {
+			checks: []string{CheckEncrypted, CheckPlainSecret},
+			want:   "sops-checker: files requiring SOPS encryption are not encrypted:\n",
+		},
+		"recipients": {
+			checks: []string{CheckRecipients},
+			want:   "sops-checker: encrypted files have unexpected recipients:\n",
+		},
+		"rotation": {
+			checks: []string{CheckRotation},
+			want:   "sops-checker: encrypted files require key rotation:\n",
+		},
+		"mixed": {
+			checks: []string{CheckEncrypted, CheckDecrypt},
+			want:   "sops-checker: files failed checks:\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := &Result{}
+			for _, check := range tt.checks {
+				result.Failures = append(result.Failures, Failure{Path: "app.yaml", Check: check, Reason: "failed"})
+			}
+
+			var out bytes.Buffer
+			if err := result.Write(&out, OutputText); err != nil {
+				t.Fatalf("Write returned error: %v", err)
+			}
+			if header, _, _ := strings.Cut(out.String(), "  - "); header != tt.want {
+				t.Fatalf("unexpected header: %q", header)
+			}
+		})
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	if format, err := ParseOutputFormat("SARIF"); err != nil || format != OutputSARIF {
+		t.Fatalf("unexpected result: %s, %v", format, err)
+	}
+	if _, err := ParseOutputFormat("xml"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/getsops/sops/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// CheckRecipients identifies encrypted files with unexpected or missing recipients.
+const CheckRecipients = "sops-recipients"
+
+// RecipientPolicy restricts the recipients encrypted files may use. Recipients
+// are identified the same way as in the SOPS metadata: age recipients, PGP
+// fingerprints, AWS KMS ARNs, GCP KMS resource IDs, Azure Key Vault key URLs
+// and HashiCorp Vault key URIs.
+type RecipientPolicy struct {
+	// Allowed contains the recipients files may be encrypted for.
+	Allowed []string `json:"allowed"`
+	// Required contains the recipients every file must be encrypted for.
+	Required []string `json:"required,omitempty"`
+
+	path string
+}
+
+// LoadRecipientPolicy loads a recipient policy file.
+func LoadRecipientPolicy(path string) (*RecipientPolicy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read recipient policy %q: %w", path, err)
+	}
+
+	policy := &RecipientPolicy{path: path}
+	if err := yaml.UnmarshalStrict(content, policy); err != nil {
+		return nil, fmt.Errorf("parse recipient policy %q: %w", path, err)
+	}
+
+	if len(policy.Allowed) == 0 {
+		return nil, fmt.Errorf("recipient policy %q allows no recipients", path)
+	}
+
+	return policy, nil
+}
+
+// Verify the recipients of an encrypted file. Without policy, the file must be
+// encrypted for exactly the keys of the matching creation rule.
+func checkRecipients(path string, tree *sops.Tree, req *requirement, policy *RecipientPolicy) *Failure {
+	actual := keyGroupRecipients(tree.Metadata.KeyGroups)
+
+	var allowed, required []string
+
+	rule := req.Rule
+
+	switch {
+	case policy != nil:
+		allowed = normalizeRecipients(policy.Allowed)
+		required = normalizeRecipients(policy.Required)
+		rule = "policy " + policy.path
+	case req.Creation != nil:
+		allowed = keyGroupRecipients(req.Creation.KeyGroups)
+		required = allowed
+	default:
+		return nil
+	}
+
+	var unexpected, missing []string
+
+	for _, recipient := range actual {
+		if !slices.Contains(allowed, recipient) {
+			unexpected = append(unexpected, recipient)
+		}
+	}
+
+	for _, recipient := range required {
+		if !slices.Contains(actual, recipient) {
+			missing = append(missing, recipient)
+		}
+	}
+
+	if len(unexpected) == 0 && len(missing) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, 0, 2)
+	if len(unexpected) > 0 {
+		reasons = append(reasons, "encrypted for unexpected recipients: "+strings.Join(unexpected, ", "))
+	}
+
+	if len(missing) > 0 {
+		reasons = append(reasons, "not encrypted for required recipients: "+strings.Join(missing, ", "))
+	}
+
+	return &Failure{
+		Path:   path,
+		Check:  CheckRecipients,
+		Rule:   rule,
+		Reason: strings.Join(reasons, "; "),
+	}
+}
+
+// Sorted, distinct recipients of all key groups.
+func keyGroupRecipients(groups []sops.KeyGroup) []string {
+	recipients := make([]string, 0)
+
+	for _, group := range groups {
+		for _, key := range group {
+			recipients = append(recipients, key.ToString())
+		}
+	}
+
+	return normalizeRecipients(recipients)
+}
+
+func normalizeRecipients(recipients []string) []string {
+	normalized := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			normalized = append(normalized, recipient)
+		}
+	}
+
+	slices.Sort(normalized)
+
+	return slices.Compact(normalized)
+}
@@ -0,0 +1,156 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const otherAgeRecipient = "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"
+
+func TestRunVerifiesRecipientsAgainstCreationRule(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+otherAgeRecipient+`
+`)
+	writeFile(t, dir, "app.secret.yaml", encryptedYAML())
+
+	result, err := Run(Options{
+		WorkDir:          dir,
+		Files:            []string{"app.secret.yaml"},
+		VerifyRecipients: true,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d", len(result.Failures))
+	}
+
+	failure := result.Failures[0]
+	if failure.Check != CheckRecipients {
+		t.Fatalf("unexpected check: %s", failure.Check)
+	}
+	if failure.Rule != `creation_rules[0] (path_regex: \.secret\.yaml$)` {
+		t.Fatalf("unexpected rule: %s", failure.Rule)
+	}
+	if !strings.Contains(failure.Reason, "unexpected recipients: "+testAgeRecipient) {
+		t.Fatalf("unexpected reason: %s", failure.Reason)
+	}
+	if !strings.Contains(failure.Reason, "required recipients: "+otherAgeRecipient) {
+		t.Fatalf("unexpected reason: %s", failure.Reason)
+	}
+}
+
+func TestRunPassesMatchingRecipients(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, ".sops.yaml", `creation_rules:
+  - path_regex: \.secret\.yaml$
+    age: `+testAgeRecipient+`
+`)
+	writeFile(t, dir, "app.secret.yaml", encryptedYAML())
+
+	result, err := Run(Options{
+		WorkDir:          dir,
+		Files:            []string{"app.secret.yaml"},
+		VerifyRecipients: true,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if len(result.Failures) != 0 {
+		t.Fatalf("expected no failures, got %#v", result.Failures)
+	}
+}
+
+func TestRunVerifiesRecipientsAgainstPolicy(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.secret.yaml", encryptedYAML())
+
+	tests := []struct {
+		name   string
+		policy string
+		reason string
+	}{
+		{
+			name:   "allowed",
+			policy: "allowed:\n  - " + testAgeRecipient + "\n  - " + otherAgeRecipient + "\n",
+		},
+		{
+			name:   "unexpected",
+			policy: "allowed:\n  - " + otherAgeRecipient + "\n",
+			reason: "encrypted for unexpected recipients: " + testAgeRecipient,
+		},
+		{
+			name: "missing",
+			policy: "allowed:\n  - " + testAgeRecipient + "\n  - " + otherAgeRecipient + "\n" +
+				"required:\n  - " + otherAgeRecipient + "\n",
+			reason: "not encrypted for required recipients: " + otherAgeRecipient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeFile(t, dir, "policy.yaml", tt.policy)
+
+			result, err := Run(Options{
+				WorkDir:    dir,
+				Files:      []string{"app.secret.yaml"},
+				RequireAll: true,
+				PolicyPath: "policy.yaml",
+			})
+			if err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+
+			if tt.reason == "" {
+				if len(result.Failures) != 0 {
+					t.Fatalf("expected no failures, got %#v", result.Failures)
+				}
+
+				return
+			}
+
+			if len(result.Failures) != 1 {
+				t.Fatalf("expected 1 failure, got %d", len(result.Failures))
+			}
+			if result.Failures[0].Reason != tt.reason {
+				t.Fatalf("unexpected reason: %s", result.Failures[0].Reason)
+			}
+			if !strings.HasPrefix(result.Failures[0].Rule, "policy ") {
+				t.Fatalf("unexpected rule: %s", result.Failures[0].Rule)
+			}
+		})
+	}
+}
+
+func TestRunRequiresPolicyForRequireAllRecipients(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "app.secret.yaml", encryptedYAML())
+
+	_, err := Run(Options{
+		WorkDir:          dir,
+		Files:            []string{"app.secret.yaml"},
+		RequireAll:       true,
+		VerifyRecipients: true,
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestLoadRecipientPolicyRejectsInvalidPolicy(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "empty.yaml", "required: []\n")
+	writeFile(t, dir, "unknown.yaml", "allow:\n  - "+testAgeRecipient+"\n")
+
+	for _, name := range []string{"empty.yaml", "unknown.yaml", "missing.yaml"} {
+		if _, err := LoadRecipientPolicy(filepath.Join(dir, name)); err == nil {
+			t.Fatalf("expected error for %s", name)
+		}
+	}
+}
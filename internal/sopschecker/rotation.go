@@ -0,0 +1,137 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getsops/sops/v3"
+)
+
+// CheckRotation identifies encrypted files whose data key or master keys
+// exceed the maximum age.
+const CheckRotation = "sops-rotation"
+
+// now is replaced in tests.
+var now = time.Now
+
+// ParseMaxAge parses a duration, which in addition to time.ParseDuration units
+// supports days, such as "90d".
+func ParseMaxAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", value)
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q", value)
+	}
+
+	return age, nil
+}
+
+// Verify the data key, based on the last modification, and the master keys,
+// based on their creation date, are not older than maxAge. Master keys
+// without creation date, such as age recipients, are checked with their
+// NeedsRotation implementation.
+func checkRotation(path string, tree *sops.Tree, rule string, maxAge time.Duration, commands bool) *Failure {
+	reasons := make([]string, 0)
+	staleMasterKeys := false
+
+	if age := now().Sub(tree.Metadata.LastModified); age > maxAge {
+		reasons = append(reasons, fmt.Sprintf("data key last modified %s ago (%s)",
+			formatAge(age), tree.Metadata.LastModified.UTC().Format(time.RFC3339)))
+	}
+
+	for _, group := range tree.Metadata.KeyGroups {
+		for _, key := range group {
+			created, ok := keyCreationDate(key.ToMap())
+
+			switch {
+			case ok:
+				age := now().Sub(created)
+				if age <= maxAge {
+					continue
+				}
+
+				reasons = append(reasons, fmt.Sprintf("%s key %s created %s ago (%s)",
+					key.TypeToIdentifier(), key.ToString(), formatAge(age), created.UTC().Format(time.RFC3339)))
+			case key.NeedsRotation():
+				reasons = append(reasons, fmt.Sprintf("%s key %s needs rotation", key.TypeToIdentifier(), key.ToString()))
+			default:
+				continue
+			}
+
+			staleMasterKeys = true
+		}
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	failure := &Failure{
+		Path:   path,
+		Check:  CheckRotation,
+		Rule:   "max age " + formatAge(maxAge),
+		Reason: strings.Join(reasons, "; "),
+	}
+
+	if rule != requireAllRule {
+		failure.Rule = rule + ", " + failure.Rule
+	}
+
+	if commands {
+		// updatekeys applies changed master keys from .sops.yaml, rotate
+		// generates a new data key and encrypts it for all master keys.
+		if staleMasterKeys {
+			failure.Commands = append(failure.Commands, "sops updatekeys --yes "+quoteArg(path))
+		}
+
+		failure.Commands = append(failure.Commands, "sops rotate --in-place "+quoteArg(path))
+	}
+
+	return failure
+}
+
+func keyCreationDate(values map[string]any) (time.Time, bool) {
+	value, ok := values["created_at"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	created, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return created, true
+}
+
+// Formats an age in days, or as duration below a day.
+func formatAge(age time.Duration) string {
+	if age < 24*time.Hour {
+		return age.Round(time.Second).String()
+	}
+
+	return strconv.Itoa(int(math.Floor(age.Hours()/24))) + "d"
+}
+
+// Quotes a shell argument when required.
+func quoteArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
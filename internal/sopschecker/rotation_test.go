@@ -0,0 +1,131 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package sopschecker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMaxAge(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90d": 90 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+		"0d":  0,
+	}
+
+	for value, expected := range tests {
+		age, err := ParseMaxAge(value)
+		if err != nil {
+			t.Fatalf("ParseMaxAge(%q) returned error: %v", value, err)
+		}
+		if age != expected {
+			t.Fatalf("ParseMaxAge(%q) = %s, expected %s", value, age, expected)
+		}
+	}
+
+	for _, value := range []string{"", "d", "-1d", "ninety days", "-1h"} {
+		if _, err := ParseMaxAge(value); err == nil {
+			t.Fatalf("expected error for %q", value)
+		}
+	}
+}
+
+func TestRunReportsStaleKeys(t *testing.T) {
+	lastModified := time.Date(2025, time.September, 2, 11, 21, 15, 0, time.UTC)
+
+	defer func(original func() time.Time) { now = original }(now)
+
+	dir := t.TempDir()
+	writeFile(t, dir, "app.secret.yaml", encryptedYAML())
+	writeFile(t, dir, "kms.secret.yaml", `data: ENC[AES256_GCM,data:abcd,iv:abcd,tag:abcd,type:str]
+sops:
+    kms:
+        - arn: arn:aws:kms:eu-central-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab
+          created_at: "2025-01-01T00:00:00Z"
+          enc: encrypted-data-key
+          aws_profile: ""
+    lastmodified: "2025-09-02T11:21:15Z"
+    mac: ENC[AES256_GCM,data:abcd,iv:abcd,tag:abcd,type:str]
+    version: 3.8.1
+`)
+
+	t.Run("fresh", func(t *testing.T) {
+		now = func() time.Time { return lastModified.Add(24 * time.Hour) }
+
+		result, err := Run(Options{
+			WorkDir:    dir,
+			Files:      []string{"app.secret.yaml"},
+			RequireAll: true,
+			MaxKeyAge:  90 * 24 * time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		if len(result.Failures) != 0 {
+			t.Fatalf("expected no failures, got %#v", result.Failures)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		now = func() time.Time { return lastModified.Add(100 * 24 * time.Hour) }
+
+		result, err := Run(Options{
+			WorkDir:          dir,
+			Files:            []string{"app.secret.yaml", "kms.secret.yaml"},
+			RequireAll:       true,
+			MaxKeyAge:        90 * 24 * time.Hour,
+			RotationCommands: true,
+		})
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+		if len(result.Failures) != 2 {
+			t.Fatalf("expected 2 failures, got %#v", result.Failures)
+		}
+
+		app := result.Failures[0]
+		if app.Check != CheckRotation || app.Rule != "max age 90d" {
+			t.Fatalf("unexpected failure: %#v", app)
+		}
+		if app.Reason != "data key last modified 100d ago (2025-09-02T11:21:15Z)" {
+			t.Fatalf("unexpected reason: %s", app.Reason)
+		}
+		if strings.Join(app.Commands, "\n") != "sops rotate --in-place app.secret.yaml" {
+			t.Fatalf("unexpected commands: %v", app.Commands)
+		}
+
+		kms := result.Failures[1]
+		if !strings.Contains(kms.Reason, "kms key arn:aws:kms:eu-central-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab created 344d ago") {
+			t.Fatalf("unexpected reason: %s", kms.Reason)
+		}
+		if strings.Join(kms.Commands, "\n") != "sops updatekeys --yes kms.secret.yaml\nsops rotate --in-place kms.secret.yaml" {
+			t.Fatalf("unexpected commands: %v", kms.Commands)
+		}
+
+		var out bytes.Buffer
+		if err := result.Write(&out, OutputText); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+		if !strings.Contains(out.String(), "      $ sops rotate --in-place app.secret.yaml\n") {
+			t.Fatalf("unexpected text output: %s", out.String())
+		}
+	})
+}
+
+func TestQuoteArg(t *testing.T) {
+	tests := map[string]string{
+		"secrets/app.yaml":  "secrets/app.yaml",
+		"my secrets/a.yaml": "'my secrets/a.yaml'",
+		"it's.yaml":         `'it'\''s.yaml'`,
+	}
+
+	for arg, expected := range tests {
+		if quoted := quoteArg(arg); quoted != expected {
+			t.Fatalf("quoteArg(%q) = %s, expected %s", arg, quoted, expected)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024-2025 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer creating the spans of the operator.
+	TracerName = "github.com/peak-scale/sops-operator"
+	// ServiceName is the default service name of the exported spans. It can be
+	// overwritten with the OTEL_SERVICE_NAME environment variable.
+	ServiceName = "sops-operator"
+)
+
+// Options configures the export of spans.
+type Options struct {
+	// Endpoint of the OTLP gRPC collector. Tracing is disabled when empty.
+	Endpoint string
+	// Insecure disables TLS for the connection to the collector.
+	Insecure bool
+	// SampleRatio is the ratio of the traces which are sampled.
+	SampleRatio float64
+}
+
+// BindFlags binds the tracing options to flags.
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Endpoint, "tracing-endpoint", "", "The OTLP gRPC endpoint (host:port) spans are exported to. Tracing is disabled when empty.")
+	fs.BoolVar(&o.Insecure, "tracing-insecure", false, "Disable TLS for the connection to the tracing endpoint")
+	fs.Float64Var(&o.SampleRatio, "tracing-sample-ratio", 1, "The ratio of traces which are sampled (0 to 1)")
+}
+
+// Setup installs a global tracer provider exporting the spans to the
+// configured endpoint. The returned function flushes the pending spans and
+// must be called on shutdown. Without endpoint, nothing is installed and spans
+// are not recorded.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid sample ratio %v: must be between 0 and 1", opts.SampleRatio)
+	}
+
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create trace exporter: %w", err)
+	}
+
+	provider, err := NewProvider(ctx, exporter, opts.SampleRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// NewProvider returns a tracer provider exporting the sampled spans in batches.
+// Spans of sampled parents are always sampled.
+func NewProvider(ctx context.Context, exporter sdktrace.SpanExporter, sampleRatio float64) (*sdktrace.TracerProvider, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	), nil
+}
+
+// Start starts a span with the global tracer provider. Without provider the
+// span is not recorded.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends a span, recording the error if any.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
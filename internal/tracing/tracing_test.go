@@ -0,0 +1,79 @@
+// Copyright 2024-2026 Peak Scale
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartEnd(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	provider, err := NewProvider(context.Background(), exporter, 1)
+	require.NoError(t, err)
+
+	otel.SetTracerProvider(provider)
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, errors.New("failed"))
+	End(parent, nil)
+
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	require.Equal(t, codes.Error, spans[0].Status.Code)
+	require.Equal(t, "failed", spans[0].Status.Description)
+	require.Len(t, spans[0].Events, 1)
+
+	require.Equal(t, "parent", spans[1].Name)
+	require.Equal(t, codes.Unset, spans[1].Status.Code)
+
+	serviceName, ok := spans[1].Resource.Set().Value("service.name")
+	require.True(t, ok)
+	require.Equal(t, ServiceName, serviceName.AsString())
+}
+
+func TestSampleRatio(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	provider, err := NewProvider(context.Background(), exporter, 0)
+	require.NoError(t, err)
+
+	_, span := provider.Tracer(TracerName).Start(context.Background(), "dropped")
+	span.End()
+
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	require.Empty(t, exporter.GetSpans())
+}
+
+func TestSetup(t *testing.T) {
+	var opts Options
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts.BindFlags(fs)
+	require.NoError(t, fs.Parse([]string{"--tracing-sample-ratio=2"}))
+
+	// Disabled without endpoint
+	shutdown, err := Setup(context.Background(), opts)
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	opts.Endpoint = "localhost:4317"
+
+	_, err = Setup(context.Background(), opts)
+	require.ErrorContains(t, err, "invalid sample ratio")
+}